@@ -0,0 +1,133 @@
+package auth
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// RoleAdmin 可以操作所有团队的 pod
+const RoleAdmin = "admin"
+
+// Claims 是调用方 token 中的身份信息
+type Claims struct {
+	//用户名，记录到版本的操作人
+	Subject string `json:"sub"`
+	//所属团队
+	Teams []int64 `json:"teams"`
+	Role  string  `json:"role"`
+	//过期时间，unix 秒
+	ExpiresAt int64 `json:"exp"`
+	NotBefore int64 `json:"nbf"`
+}
+
+// IsAdmin 判断是否为管理员
+func (c *Claims) IsAdmin() bool {
+	return c.Role == RoleAdmin
+}
+
+// InTeam 判断是否属于指定团队
+func (c *Claims) InTeam(teamID int64) bool {
+	for _, team := range c.Teams {
+		if team == teamID {
+			return true
+		}
+	}
+	return false
+}
+
+// Verifier 使用本地密钥校验 HS256 签名的 JWT
+type Verifier struct {
+	key []byte
+}
+
+func NewVerifier(key []byte) (*Verifier, error) {
+	if len(key) < 32 {
+		return nil, errors.New("jwt 密钥长度不能少于 32 字节")
+	}
+	return &Verifier{key: key}, nil
+}
+
+// LoadKeyFile 从本地文件读取 jwt 密钥，忽略首尾空白
+func LoadKeyFile(path string) (*Verifier, error) {
+	key, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取 jwt 密钥文件失败: %v", err)
+	}
+	return NewVerifier(bytes.TrimSpace(key))
+}
+
+// Verify 校验签名和有效期，返回 token 中的身份信息
+func (v *Verifier) Verify(token string) (*Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, errors.New("token 格式错误")
+	}
+	header := struct {
+		Alg string `json:"alg"`
+	}{}
+	if err := decodeSegment(parts[0], &header); err != nil {
+		return nil, err
+	}
+	//只接受 HS256，防止 alg 为 none 等绕过签名
+	if header.Alg != "HS256" {
+		return nil, fmt.Errorf("不支持的签名算法: %s", header.Alg)
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, errors.New("token 签名格式错误")
+	}
+	if !hmac.Equal(signature, v.sign(parts[0]+"."+parts[1])) {
+		return nil, errors.New("token 签名错误")
+	}
+	claims := &Claims{}
+	if err := decodeSegment(parts[1], claims); err != nil {
+		return nil, err
+	}
+	now := time.Now().Unix()
+	if claims.ExpiresAt != 0 && now >= claims.ExpiresAt {
+		return nil, errors.New("token 已过期")
+	}
+	if claims.NotBefore != 0 && now < claims.NotBefore {
+		return nil, errors.New("token 尚未生效")
+	}
+	if claims.Subject == "" {
+		return nil, errors.New("token 中没有用户")
+	}
+	return claims, nil
+}
+
+// Sign 生成 token，供运维工具和其他服务签发使用
+func (v *Verifier) Sign(claims *Claims) (string, error) {
+	header := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	unsigned := header + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(v.sign(unsigned)), nil
+}
+
+func (v *Verifier) sign(data string) []byte {
+	mac := hmac.New(sha256.New, v.key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func decodeSegment(segment string, target interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(segment)
+	if err != nil {
+		return errors.New("token 格式错误")
+	}
+	if err := json.Unmarshal(data, target); err != nil {
+		return errors.New("token 内容格式错误")
+	}
+	return nil
+}
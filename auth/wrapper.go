@@ -0,0 +1,63 @@
+package auth
+
+import (
+	"context"
+	"strings"
+
+	"github.com/asim/go-micro/v3/metadata"
+	"github.com/asim/go-micro/v3/server"
+	"github.com/jary-287/gopass-pod/service"
+)
+
+type claimsKey struct{}
+
+// Policy 在 handler 执行之前按请求内容检查调用方权限，流式请求的 Body 为 nil，需要 handler 自己检查
+type Policy func(ctx context.Context, claims *Claims, req server.Request) error
+
+// NewContext 把身份信息放入 context
+func NewContext(ctx context.Context, claims *Claims) context.Context {
+	return context.WithValue(ctx, claimsKey{}, claims)
+}
+
+// FromContext 取出调用方身份，没有开启鉴权时返回 false
+func FromContext(ctx context.Context) (*Claims, bool) {
+	claims, ok := ctx.Value(claimsKey{}).(*Claims)
+	return claims, ok
+}
+
+// NewHandlerWrapper 从请求元数据的 Authorization 中读取并校验 token，再交给 policy 检查权限
+func NewHandlerWrapper(verifier *Verifier, policy Policy) server.HandlerWrapper {
+	return func(fn server.HandlerFunc) server.HandlerFunc {
+		return func(ctx context.Context, req server.Request, rsp interface{}) error {
+			token, ok := metadata.Get(ctx, "Authorization")
+			if !ok || token == "" {
+				return service.ToMicroError(service.NewUnauthenticatedError("缺少 Authorization"))
+			}
+			claims, err := verifier.Verify(strings.TrimSpace(strings.TrimPrefix(token, "Bearer ")))
+			if err != nil {
+				return service.ToMicroError(service.NewUnauthenticatedError("%s", err.Error()))
+			}
+			ctx = NewContext(ctx, claims)
+			if policy != nil {
+				if err := policy(ctx, claims, req); err != nil {
+					return service.ToMicroError(err)
+				}
+			}
+			return fn(ctx, req, rsp)
+		}
+	}
+}
+
+// CheckTeam 检查调用方是否可以操作指定团队的资源，没有开启鉴权或者是管理员时直接通过
+func CheckTeam(ctx context.Context, teamID int64) error {
+	claims, ok := FromContext(ctx)
+	if !ok || claims.IsAdmin() || claims.InTeam(teamID) {
+		return nil
+	}
+	return Forbidden("用户 %s 不属于团队 %d", claims.Subject, teamID)
+}
+
+// Forbidden 返回 FORBIDDEN 错误码
+func Forbidden(format string, a ...interface{}) error {
+	return service.NewForbiddenError(format, a...)
+}
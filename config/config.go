@@ -0,0 +1,304 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jary-287/gopass-common/common"
+	"gopkg.in/yaml.v3"
+	"k8s.io/client-go/util/homedir"
+)
+
+// EnvPrefix 环境变量前缀，flag 名称转为大写、- 换成 _ 后加上前缀，如 -registry-address 对应 POD_REGISTRY_ADDRESS
+const EnvPrefix = "POD_"
+
+// Config 是服务的全部配置，优先级为 flag > 环境变量 > yaml 文件 > 默认值
+type Config struct {
+	Service   ServiceConfig   `yaml:"service"`
+	Registry  RegistryConfig  `yaml:"registry"`
+	Tracer    TracerConfig    `yaml:"tracer"`
+	Storage   StorageConfig   `yaml:"storage"`
+	Mysql     MysqlConfig     `yaml:"mysql"`
+	Reconcile ReconcileConfig `yaml:"reconcile"`
+	//kubeconfig 位置
+	Kubeconfig string `yaml:"kubeconfig"`
+	//敏感字段加密使用的密钥文件,为空时不加密
+	KeyFile string `yaml:"key_file"`
+	//配置密钥文件后仍然读取加密之前写入的明文,只在 reencrypt 完成之前开启
+	AllowPlaintext bool `yaml:"allow_plaintext"`
+	//校验调用方 jwt 的密钥文件,为空时不鉴权
+	JwtKeyFile string `yaml:"jwt_key_file"`
+
+	//yaml 配置文件位置，只能通过 flag 或环境变量指定
+	File string `yaml:"-"`
+	//flag 之后的参数，即子命令
+	Args []string `yaml:"-"`
+}
+
+type ServiceConfig struct {
+	Name    string `yaml:"name"`
+	Version string `yaml:"version"`
+	//监听地址
+	Address string `yaml:"address"`
+	//每秒处理的请求数上限
+	RateLimit int `yaml:"rate_limit"`
+}
+
+// RegistryConfig 是 consul 注册中心的配置，mysql 没有配置 dsn 时也从这里的配置中心读取
+type RegistryConfig struct {
+	Address string        `yaml:"address"`
+	Timeout time.Duration `yaml:"timeout"`
+}
+
+type TracerConfig struct {
+	//jaeger agent 地址，为空时不开启链路追踪
+	Address string `yaml:"address"`
+}
+
+type StorageConfig struct {
+	//mysql/sqlite/memory
+	Driver string `yaml:"driver"`
+	//sqlite 数据库文件
+	SqlitePath string `yaml:"sqlite_path"`
+	//启动时执行未执行的表结构迁移，关闭后数据库不是最新版本时拒绝启动，需要先执行 migrate up
+	AutoMigrate bool `yaml:"auto_migrate"`
+}
+
+type MysqlConfig struct {
+	//为空时从 consul 配置中心的 <consul_prefix>/mysql 读取连接信息
+	DSN          string `yaml:"dsn"`
+	ConsulPrefix string `yaml:"consul_prefix"`
+}
+
+type ReconcileConfig struct {
+	//pod 表与集群调和周期,0 表示关闭
+	Interval time.Duration `yaml:"interval"`
+	//只报告差异,不修改集群
+	DryRun bool `yaml:"dry_run"`
+}
+
+// Default 返回本地开发使用的默认配置
+func Default() *Config {
+	kubeconfig := ""
+	if home := homedir.HomeDir(); home != "" {
+		kubeconfig = path.Join(home, ".kube", "config")
+	}
+	return &Config{
+		Service: ServiceConfig{
+			Name:      "service.pod",
+			Version:   "latest",
+			Address:   ":8888",
+			RateLimit: 1000,
+		},
+		Registry: RegistryConfig{
+			Address: "192.168.0.19:8500",
+			Timeout: 20 * time.Second,
+		},
+		Tracer: TracerConfig{
+			Address: ":9333",
+		},
+		Storage: StorageConfig{
+			Driver:      "mysql",
+			SqlitePath:  "pod.db",
+			AutoMigrate: true,
+		},
+		Mysql: MysqlConfig{
+			ConsulPrefix: "micro/config",
+		},
+		Reconcile: ReconcileConfig{
+			Interval: 5 * time.Minute,
+			DryRun:   true,
+		},
+		Kubeconfig: kubeconfig,
+	}
+}
+
+// 每个 flag 都绑定到 cfg 的字段上，默认值取 cfg 当前的值
+func newFlagSet(cfg *Config) *flag.FlagSet {
+	fs := flag.NewFlagSet(os.Args[0], flag.ContinueOnError)
+	fs.StringVar(&cfg.File, "config", cfg.File, "yaml 配置文件位置")
+	fs.StringVar(&cfg.Service.Name, "service-name", cfg.Service.Name, "注册到 consul 的服务名")
+	fs.StringVar(&cfg.Service.Version, "service-version", cfg.Service.Version, "服务版本")
+	fs.StringVar(&cfg.Service.Address, "address", cfg.Service.Address, "服务监听地址")
+	fs.IntVar(&cfg.Service.RateLimit, "rate-limit", cfg.Service.RateLimit, "每秒处理的请求数上限")
+	fs.StringVar(&cfg.Registry.Address, "registry-address", cfg.Registry.Address, "consul 地址")
+	fs.DurationVar(&cfg.Registry.Timeout, "registry-timeout", cfg.Registry.Timeout, "consul 超时时间")
+	fs.StringVar(&cfg.Tracer.Address, "tracer-address", cfg.Tracer.Address, "jaeger agent 地址,为空时不开启链路追踪")
+	fs.StringVar(&cfg.Storage.Driver, "storage-driver", cfg.Storage.Driver, "存储类型 mysql/sqlite/memory")
+	fs.StringVar(&cfg.Storage.SqlitePath, "sqlite-path", cfg.Storage.SqlitePath, "sqlite 数据库文件")
+	fs.BoolVar(&cfg.Storage.AutoMigrate, "auto-migrate", cfg.Storage.AutoMigrate, "启动时执行表结构迁移,关闭后数据库不是最新版本时拒绝启动")
+	fs.StringVar(&cfg.Mysql.DSN, "mysql-dsn", cfg.Mysql.DSN, "mysql 连接串,为空时从 consul 配置中心读取")
+	fs.StringVar(&cfg.Mysql.ConsulPrefix, "mysql-consul-prefix", cfg.Mysql.ConsulPrefix, "consul 配置中心中 mysql 配置的前缀")
+	fs.DurationVar(&cfg.Reconcile.Interval, "reconcile-interval", cfg.Reconcile.Interval, "pod 表与集群调和周期,0 表示关闭")
+	fs.BoolVar(&cfg.Reconcile.DryRun, "reconcile-dry-run", cfg.Reconcile.DryRun, "调和只报告差异,不修改集群")
+	fs.StringVar(&cfg.Kubeconfig, "kubeconfig", cfg.Kubeconfig, "kubeconfig 位置")
+	fs.StringVar(&cfg.KeyFile, "key-file", cfg.KeyFile, "敏感字段加密使用的密钥文件,为空时不加密")
+	fs.BoolVar(&cfg.AllowPlaintext, "allow-plaintext", cfg.AllowPlaintext, "读取加密之前写入的明文,只在 reencrypt 完成之前开启")
+	fs.StringVar(&cfg.JwtKeyFile, "jwt-key-file", cfg.JwtKeyFile, "校验调用方 jwt 的密钥文件,为空时不鉴权")
+	return fs
+}
+
+// EnvName 返回 flag 对应的环境变量名
+func EnvName(flagName string) string {
+	return EnvPrefix + strings.ToUpper(strings.ReplaceAll(flagName, "-", "_"))
+}
+
+// Load 按 默认值、yaml 文件、环境变量、flag 的顺序依次覆盖，args 不包含程序名
+func Load(args []string) (*Config, error) {
+	//先解析一次 flag 找到配置文件，yaml 要在环境变量和 flag 之前加载
+	//flag 的错误在第二次解析时报告
+	probe := Default()
+	probeFlags := newFlagSet(probe)
+	probeFlags.SetOutput(io.Discard)
+	_ = probeFlags.Parse(args)
+	file := probe.File
+	if file == "" {
+		file = os.Getenv(EnvName("config"))
+	}
+	cfg := Default()
+	if file != "" {
+		if err := cfg.loadFile(file); err != nil {
+			return nil, err
+		}
+	}
+	fs := newFlagSet(cfg)
+	var envErr error
+	fs.VisitAll(func(f *flag.Flag) {
+		value, ok := os.LookupEnv(EnvName(f.Name))
+		if !ok || envErr != nil {
+			return
+		}
+		if err := fs.Set(f.Name, value); err != nil {
+			envErr = fmt.Errorf("环境变量 %s 的值不合法: %v", EnvName(f.Name), err)
+		}
+	})
+	if envErr != nil {
+		return nil, envErr
+	}
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	cfg.File = file
+	cfg.Args = fs.Args()
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+// 读取 yaml 配置文件，不认识的字段视为错误，避免写错的配置被静默忽略
+func (c *Config) loadFile(file string) error {
+	data, err := os.ReadFile(file)
+	if err != nil {
+		return fmt.Errorf("读取配置文件失败: %v", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("解析配置文件 %s 失败: %v", file, err)
+	}
+	return nil
+}
+
+// Validate 检查配置是否完整合法
+func (c *Config) Validate() error {
+	if c.Service.Name == "" {
+		return errors.New("service.name 不能为空")
+	}
+	if c.Service.Address == "" {
+		return errors.New("service.address 不能为空")
+	}
+	if _, _, err := net.SplitHostPort(c.Service.Address); err != nil {
+		return fmt.Errorf("service.address 格式错误: %v", err)
+	}
+	if c.Service.RateLimit <= 0 {
+		return fmt.Errorf("service.rate_limit 必须大于 0: %d", c.Service.RateLimit)
+	}
+	if _, _, err := c.Registry.HostPort(); err != nil {
+		return err
+	}
+	if c.Registry.Timeout <= 0 {
+		return fmt.Errorf("registry.timeout 必须大于 0: %s", c.Registry.Timeout)
+	}
+	if c.Tracer.Address != "" {
+		if _, _, err := net.SplitHostPort(c.Tracer.Address); err != nil {
+			return fmt.Errorf("tracer.address 格式错误: %v", err)
+		}
+	}
+	switch c.Storage.Driver {
+	case "mysql":
+		if c.Mysql.DSN == "" && c.Mysql.ConsulPrefix == "" {
+			return errors.New("mysql.dsn 和 mysql.consul_prefix 不能都为空")
+		}
+	case "sqlite":
+		if c.Storage.SqlitePath == "" {
+			return errors.New("storage.sqlite_path 不能为空")
+		}
+	case "memory":
+	default:
+		return fmt.Errorf("storage.driver 不支持: %s,可选值 mysql/sqlite/memory", c.Storage.Driver)
+	}
+	if c.Reconcile.Interval < 0 {
+		return fmt.Errorf("reconcile.interval 不能小于 0: %s", c.Reconcile.Interval)
+	}
+	return nil
+}
+
+// HostPort 拆分 consul 地址
+func (r *RegistryConfig) HostPort() (string, int64, error) {
+	host, port, err := net.SplitHostPort(r.Address)
+	if err != nil {
+		return "", 0, fmt.Errorf("registry.address 格式错误: %v", err)
+	}
+	portNumber, err := strconv.ParseInt(port, 10, 64)
+	if err != nil || host == "" {
+		return "", 0, fmt.Errorf("registry.address 格式错误: %s", r.Address)
+	}
+	return host, portNumber, nil
+}
+
+// StorageDSN 返回存储的连接串，sqlite 为文件路径，内存存储为空
+func (c *Config) StorageDSN() (string, error) {
+	switch c.Storage.Driver {
+	case "sqlite":
+		return c.Storage.SqlitePath, nil
+	case "memory":
+		return "", nil
+	}
+	return c.MysqlDSN()
+}
+
+// MysqlDSN 返回 mysql 连接串，没有配置 dsn 时从 consul 配置中心读取
+func (c *Config) MysqlDSN() (string, error) {
+	if c.Mysql.DSN != "" {
+		return c.Mysql.DSN, nil
+	}
+	host, port, err := c.Registry.HostPort()
+	if err != nil {
+		return "", err
+	}
+	consulConfig, err := common.GetConsulConfig(host, port, c.Mysql.ConsulPrefix)
+	if err != nil {
+		return "", fmt.Errorf("读取 consul 配置中心失败: %v", err)
+	}
+	prefix := strings.Split(strings.Trim(c.Mysql.ConsulPrefix, "/"), "/")
+	mysqlInfo := common.GetMysqlFromConsul(consulConfig, append(prefix, "mysql")...)
+	if mysqlInfo.Host == "" {
+		return "", fmt.Errorf("consul 配置中心 %s/mysql 中没有 mysql 配置", c.Mysql.ConsulPrefix)
+	}
+	return fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?charset=utf8mb4&parseTime=True&loc=Local",
+		mysqlInfo.User,
+		mysqlInfo.PassWord,
+		mysqlInfo.Host,
+		mysqlInfo.Port,
+		mysqlInfo.Database), nil
+}
@@ -0,0 +1,106 @@
+package handle
+
+import (
+	"context"
+
+	"github.com/asim/go-micro/v3/server"
+	"github.com/jary-287/gopass-pod/auth"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service"
+)
+
+// Authorize 按请求中的 pod 检查调用方是否属于 pod 所在的团队，作为 auth.Policy 使用
+func (ph *Podhandler) Authorize(ctx context.Context, claims *auth.Claims, req server.Request) error {
+	if claims.IsAdmin() {
+		return nil
+	}
+	switch body := req.Body().(type) {
+	case *pod.PodInfo:
+		if req.Endpoint() == "Pod.AddPod" {
+			return auth.CheckTeam(ctx, body.PodTeamId)
+		}
+		podModel, err := ph.PodService.FindPodById(body.PodId)
+		if err != nil {
+			//没有记录时删除会按名称清理集群中的残留，只允许管理员操作
+			if req.Endpoint() == "Pod.DeletePod" {
+				return auth.Forbidden("pod 记录不存在,只有管理员可以清理集群中的残留")
+			}
+			return nil
+		}
+		if err := auth.CheckTeam(ctx, podModel.PodTeamID); err != nil {
+			return err
+		}
+		if req.Endpoint() == "Pod.UpdatePod" {
+			//k8s 按请求中的名称和命名空间修改，必须与鉴权使用的记录一致，否则会修改其他团队的工作负载
+			if !service.SameWorkload(podModel, body) {
+				return auth.Forbidden("pod_name、pod_namespace 和 pod_deploy_type 必须与 pod id:%d 的记录一致", podModel.PodID)
+			}
+			//更新后的团队也必须是调用方所在的团队
+			return auth.CheckTeam(ctx, body.PodTeamId)
+		}
+		return nil
+	case *pod.PodId:
+		return ph.checkPodTeam(ctx, body.Id)
+	case *pod.ScaleRequest:
+		return ph.checkPodTeam(ctx, body.PodId)
+	case *pod.PatchPodRequest:
+		if err := ph.checkPodTeam(ctx, body.PodId); err != nil {
+			return err
+		}
+		//修改团队时新的团队也必须是调用方所在的团队
+		for _, path := range body.UpdateMask.GetPaths() {
+			if path == "pod_team_id" {
+				return auth.CheckTeam(ctx, body.Pod.GetPodTeamId())
+			}
+		}
+		return nil
+	case *pod.RollbackRequest:
+		if err := ph.checkPodTeam(ctx, body.PodId); err != nil {
+			return err
+		}
+		//回滚会恢复快照中的团队
+		if spec, err := ph.PodService.FindRevision(body.PodId, body.Revision); err == nil {
+			return auth.CheckTeam(ctx, spec.PodTeamId)
+		}
+		return nil
+	case *pod.FindAll:
+		return restrictTeams(claims, body)
+	case *pod.TeamQuota:
+		return auth.Forbidden("只有管理员可以修改团队配额")
+	case *pod.TeamId:
+		return auth.CheckTeam(ctx, body.Id)
+	}
+	//流式请求在 handler 中检查
+	return nil
+}
+
+// 检查数据库中 pod 的团队，pod 不存在时交给 handler 返回错误
+func (ph *Podhandler) checkPodTeam(ctx context.Context, podID uint64) error {
+	podModel, err := ph.PodService.FindPodById(podID)
+	if err != nil {
+		return nil
+	}
+	return auth.CheckTeam(ctx, podModel.PodTeamID)
+}
+
+// 把查询条件限制在调用方所在的团队内
+func restrictTeams(claims *auth.Claims, findAll *pod.FindAll) error {
+	if findAll.PodTeamId != 0 && !claims.InTeam(findAll.PodTeamId) {
+		return auth.Forbidden("用户 %s 不属于团队 %d", claims.Subject, findAll.PodTeamId)
+	}
+	if len(findAll.PodTeamIds) == 0 {
+		findAll.PodTeamIds = claims.Teams
+	} else {
+		var teams []int64
+		for _, team := range findAll.PodTeamIds {
+			if claims.InTeam(team) {
+				teams = append(teams, team)
+			}
+		}
+		findAll.PodTeamIds = teams
+	}
+	if len(findAll.PodTeamIds) == 0 {
+		return auth.Forbidden("用户 %s 不属于查询的任何团队", claims.Subject)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+package handle
+
+import (
+	"context"
+	"testing"
+
+	"github.com/asim/go-micro/v3/server"
+	"github.com/jary-287/gopass-pod/auth"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service"
+)
+
+// 只实现 Authorize 用到的方法
+type testRequest struct {
+	server.Request
+	endpoint string
+	body     interface{}
+}
+
+func (r *testRequest) Endpoint() string  { return r.endpoint }
+func (r *testRequest) Body() interface{} { return r.body }
+
+func TestAuthorize(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, ph *Podhandler) {
+		info := addTestPod(t, ph)
+		member := &auth.Claims{Subject: "alice", Teams: []int64{1}}
+		other := &auth.Claims{Subject: "bob", Teams: []int64{2}}
+		renamed := &pod.PodInfo{PodId: info.PodId, PodName: "api", PodNamespace: "default", PodTeamId: 1}
+		for _, tc := range []struct {
+			name     string
+			claims   *auth.Claims
+			endpoint string
+			body     interface{}
+			want     string
+		}{
+			{"同团队查询", member, "Pod.FindPodById", &pod.PodId{Id: info.PodId}, ""},
+			{"其他团队查询", other, "Pod.FindPodById", &pod.PodId{Id: info.PodId}, service.CodeForbidden},
+			{"同团队更新", member, "Pod.UpdatePod", info, ""},
+			{"更新时修改名称", member, "Pod.UpdatePod", renamed, service.CodeForbidden},
+			{"其他团队扩缩容", other, "Pod.ScalePod", &pod.ScaleRequest{PodId: info.PodId}, service.CodeForbidden},
+			{"修改团队配额", member, "Pod.SetTeamQuota", &pod.TeamQuota{TeamId: 1}, service.CodeForbidden},
+			{"管理员", &auth.Claims{Role: auth.RoleAdmin}, "Pod.UpdatePod", renamed, ""},
+		} {
+			t.Run(tc.name, func(t *testing.T) {
+				ctx := auth.NewContext(context.TODO(), tc.claims)
+				err := ph.Authorize(ctx, tc.claims, &testRequest{endpoint: tc.endpoint, body: tc.body})
+				if tc.want == "" && err != nil {
+					t.Fatalf("err = %v, want nil", err)
+				}
+				if tc.want != "" && service.Classify(err).Code != tc.want {
+					t.Fatalf("err = %v, want %s", err, tc.want)
+				}
+			})
+		}
+	})
+}
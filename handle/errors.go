@@ -0,0 +1,15 @@
+package handle
+
+import (
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service"
+)
+
+// 把错误码和详情写入响应，返回带错误码的 go-micro 错误
+func fail(rsp *pod.Response, err error) error {
+	podErr := service.Classify(err)
+	rsp.Msg = podErr.Message
+	rsp.Code = podErr.Code
+	rsp.Detail = podErr.Detail
+	return service.ToMicroError(podErr)
+}
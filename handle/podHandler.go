@@ -3,32 +3,32 @@ package handle
 import (
 	"context"
 	"encoding/json"
-	"errors"
+	"fmt"
 	"log"
 
-	"github.com/jary-287/gopass-pod/model"
+	"github.com/asim/go-micro/v3/metadata"
+	"github.com/jary-287/gopass-pod/auth"
 	"github.com/jary-287/gopass-pod/proto/pod"
 	"github.com/jary-287/gopass-pod/service"
+	"google.golang.org/protobuf/proto"
 )
 
 type Podhandler struct {
 	PodService service.IPodService
+	PodSaga    *service.PodSaga
 }
 
 func (ph *Podhandler) AddPod(ctx context.Context, info *pod.PodInfo, rsp *pod.Response) error {
 	log.Println("add pod :", info.PodName)
-	podModel := &model.Pod{}
-	if err := swap(info, podModel); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	if err := validate(info); err != nil {
+		return fail(rsp, err)
 	}
-	if err := ph.PodService.CreateToK8s(info); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	podModel, err := service.PodInfoToModel(info)
+	if err != nil {
+		return fail(rsp, err)
 	}
-	if _, err := ph.PodService.AddPod(podModel); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	if _, err := ph.PodSaga.Create(info, podModel, getOperator(ctx)); err != nil {
+		return fail(rsp, err)
 	}
 	log.Println("pod add success:", info.PodName)
 	rsp.Msg = "success create pod,pod name " + info.PodName
@@ -36,14 +36,17 @@ func (ph *Podhandler) AddPod(ctx context.Context, info *pod.PodInfo, rsp *pod.Re
 }
 
 func (ph *Podhandler) DeletePod(ctx context.Context, info *pod.PodInfo, rsp *pod.Response) error {
-	if err := ph.PodService.DeleteFromK8s(info); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	//以数据库中的记录为准，确定要删除的工作负载类型
+	if podModel, err := ph.PodService.FindPodById(info.PodId); err == nil {
+		info.PodName = podModel.PodName
+		info.PodNamespace = podModel.PodNameSpace
+		info.PodDeployType = podModel.PodDeployType
+	} else if service.Classify(err).Code != service.CodeNotFound {
+		//查询失败时不能按请求中的名称清理集群
+		return fail(rsp, err)
 	}
-	log.Println("delete pod from k8s success:  podname", info.PodName)
-	if err := ph.PodService.DeletePod(info.PodId); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	if err := ph.PodSaga.Delete(info); err != nil {
+		return fail(rsp, err)
 	}
 	log.Println("pod delete success:", info.PodName)
 	rsp.Msg = "success delete pod,pod name " + info.PodName
@@ -51,49 +54,213 @@ func (ph *Podhandler) DeletePod(ctx context.Context, info *pod.PodInfo, rsp *pod
 }
 
 func (ph *Podhandler) UpdatePod(ctx context.Context, info *pod.PodInfo, rsp *pod.Response) error {
-	if err := ph.PodService.UpdateToK8s(info); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	if err := validate(info); err != nil {
+		return fail(rsp, err)
 	}
-	log.Println("update pod to k8s success:", info.PodName)
-	podModel := &model.Pod{}
-	if err := swap(info, podModel); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	podModel, err := service.PodInfoToModel(info)
+	if err != nil {
+		return fail(rsp, err)
 	}
-	if err := ph.PodService.UpdatePod(podModel); err != nil {
-		rsp.Msg = err.Error()
-		return err
+	if err := ph.PodSaga.Update(info, podModel, getOperator(ctx)); err != nil {
+		return fail(rsp, err)
 	}
 	log.Println("update pod  success:", info.PodName)
 	return nil
 }
 
+// rpc PatchPod(PatchPodRequest) returns (response) {}
+func (ph *Podhandler) PatchPod(ctx context.Context, req *pod.PatchPodRequest, rsp *pod.Response) error {
+	current, err := ph.PodService.FindPodById(req.PodId)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	//以数据库中的配置为基础，只修改 update_mask 中的字段
+	info, err := service.ModelToPodInfo(current)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	if err := service.ApplyPatch(info, req.Pod, req.UpdateMask); err != nil {
+		return fail(rsp, err)
+	}
+	info.Version = req.Version
+	if err := validate(info); err != nil {
+		return fail(rsp, err)
+	}
+	podModel, err := service.PodInfoToModel(info)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	if err := ph.PodSaga.Update(info, podModel, getOperator(ctx)); err != nil {
+		return fail(rsp, err)
+	}
+	log.Println("patch pod success:", info.PodName, req.UpdateMask.GetPaths())
+	rsp.Msg = "success patch pod,pod name " + info.PodName
+	return nil
+}
+
 // rpc FindPodById(PodId) returns (PodInfo) {}
 func (ph *Podhandler) FindPodById(ctx context.Context, id *pod.PodId, info *pod.PodInfo) error {
 	podModel, err := ph.PodService.FindPodById(id.Id)
 	if err != nil {
-		return err
+		return service.ToMicroError(err)
 	}
-	if err = swap(podModel, info); err != nil {
-		return err
+	podInfo, err := service.ModelToPodInfo(podModel)
+	if err != nil {
+		return service.ToMicroError(err)
 	}
+	proto.Merge(info, podInfo)
+	service.FillResourceView(info)
+	service.MaskSecretEnvs(info)
 	log.Println("find pod by Id success")
 	return nil
 }
 
 func (ph *Podhandler) FindAllPod(ctx context.Context, findAll *pod.FindAll, allPod *pod.AllPod) error {
-	pods, err := ph.PodService.FindAllPod()
+	pods, total, nextPageToken, err := ph.PodService.QueryPods(findAll)
 	if err != nil {
-		return errors.New("find all pod failed:" + err.Error())
+		return service.ToMicroError(err)
 	}
-	if err := swap(pods, &allPod.PodInfo); err != nil {
-		return errors.New("find all pod: swap failed " + err.Error())
+	allPod.TotalCount = total
+	allPod.NextPageToken = nextPageToken
+	for i := range pods {
+		info, err := service.ModelToPodInfo(&pods[i])
+		if err != nil {
+			return service.ToMicroError(err)
+		}
+		service.FillResourceView(info)
+		service.MaskSecretEnvs(info)
+		allPod.PodInfo = append(allPod.PodInfo, info)
 	}
 	log.Println("find all pod success")
 	return nil
 }
 
+// rpc GetPodStatus(PodId) returns (PodStatus) {}
+func (ph *Podhandler) GetPodStatus(ctx context.Context, id *pod.PodId, status *pod.PodStatus) error {
+	podModel, err := ph.PodService.FindPodById(id.Id)
+	if err != nil {
+		return service.ToMicroError(err)
+	}
+	podStatus, err := ph.PodService.GetPodStatus(podModel)
+	if err != nil {
+		return service.ToMicroError(err)
+	}
+	if err = swap(podStatus, status); err != nil {
+		return service.ToMicroError(err)
+	}
+	log.Println("get pod status success:", podModel.PodName)
+	return nil
+}
+
+// rpc StreamPodLogs(PodLogRequest) returns (stream PodLogLine) {}
+func (ph *Podhandler) StreamPodLogs(ctx context.Context, req *pod.PodLogRequest, stream pod.Pod_StreamPodLogsStream) error {
+	defer stream.Close()
+	podModel, err := ph.PodService.FindPodById(req.PodId)
+	if err != nil {
+		return service.ToMicroError(err)
+	}
+	//流式请求没有经过 auth.Policy，在这里检查团队
+	if err := auth.CheckTeam(ctx, podModel.PodTeamID); err != nil {
+		return service.ToMicroError(err)
+	}
+	log.Println("stream pod logs:", podModel.PodName)
+	return service.ToMicroError(ph.PodService.StreamPodLogs(ctx, podModel, req, stream.Send))
+}
+
+// rpc ScalePod(ScaleRequest) returns (response) {}
+func (ph *Podhandler) ScalePod(ctx context.Context, req *pod.ScaleRequest, rsp *pod.Response) error {
+	podModel, err := ph.PodService.FindPodById(req.PodId)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	if err := ph.PodSaga.Scale(podModel, req.Replicas); err != nil {
+		return fail(rsp, err)
+	}
+	log.Println("scale pod success:", podModel.PodName)
+	rsp.Msg = fmt.Sprintf("success scale pod,pod name %s,replicas %d", podModel.PodName, req.Replicas)
+	return nil
+}
+
+// rpc RestartPod(PodId) returns (response) {}
+func (ph *Podhandler) RestartPod(ctx context.Context, id *pod.PodId, rsp *pod.Response) error {
+	podModel, err := ph.PodService.FindPodById(id.Id)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	if err := ph.PodService.RestartToK8s(podModel); err != nil {
+		return fail(rsp, err)
+	}
+	log.Println("restart pod success:", podModel.PodName)
+	rsp.Msg = "success restart pod,pod name " + podModel.PodName
+	return nil
+}
+
+// rpc ListPodRevisions(PodId) returns (PodRevisions) {}
+func (ph *Podhandler) ListPodRevisions(ctx context.Context, id *pod.PodId, revisions *pod.PodRevisions) error {
+	podRevisions, err := ph.PodService.FindRevisions(id.Id)
+	if err != nil {
+		return service.ToMicroError(err)
+	}
+	revisions.Revisions = podRevisions
+	log.Println("list pod revisions success:", id.Id)
+	return nil
+}
+
+// rpc RollbackPod(RollbackRequest) returns (response) {}
+func (ph *Podhandler) RollbackPod(ctx context.Context, req *pod.RollbackRequest, rsp *pod.Response) error {
+	info, err := ph.PodService.FindRevision(req.PodId, req.Revision)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	podModel, err := service.PodInfoToModel(info)
+	if err != nil {
+		return fail(rsp, err)
+	}
+	if err := ph.PodSaga.Rollback(info, podModel, req.Revision, getOperator(ctx)); err != nil {
+		return fail(rsp, err)
+	}
+	log.Printf("rollback pod success: %s to revision %d", info.PodName, req.Revision)
+	rsp.Msg = fmt.Sprintf("success rollback pod,pod name %s,revision %d", info.PodName, req.Revision)
+	return nil
+}
+
+// rpc SetTeamQuota(TeamQuota) returns (response) {}
+func (ph *Podhandler) SetTeamQuota(ctx context.Context, quota *pod.TeamQuota, rsp *pod.Response) error {
+	if err := ph.PodService.SetTeamQuota(quota); err != nil {
+		return fail(rsp, err)
+	}
+	log.Println("set team quota success:", quota.TeamId)
+	rsp.Msg = fmt.Sprintf("success set team quota,team id %d", quota.TeamId)
+	return nil
+}
+
+// rpc GetTeamQuota(TeamId) returns (TeamQuota) {}
+func (ph *Podhandler) GetTeamQuota(ctx context.Context, id *pod.TeamId, quota *pod.TeamQuota) error {
+	teamQuota, err := ph.PodService.GetTeamQuota(id.Id)
+	if err != nil {
+		return service.ToMicroError(err)
+	}
+	proto.Merge(quota, teamQuota)
+	log.Println("get team quota success:", id.Id)
+	return nil
+}
+
+// 校验请求，不合法时返回的 ValidationError 转换为 INVALID_ARGUMENT，详情中带上字段
+func validate(info *pod.PodInfo) error {
+	return service.ValidatePodInfo(info)
+}
+
+// 从 token 或请求元数据中取操作人，用于记录版本
+func getOperator(ctx context.Context) string {
+	if claims, ok := auth.FromContext(ctx); ok {
+		return claims.Subject
+	}
+	if user, ok := metadata.Get(ctx, "User"); ok {
+		return user
+	}
+	return "unknown"
+}
+
 //proroto打包成json，在解到struct
 func swap(source interface{}, target interface{}) error {
 	data, err := json.Marshal(source)
@@ -0,0 +1,157 @@
+package handle
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service"
+	"github.com/jary-287/gopass-pod/service/servicetest"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// 每种存储各创建一个使用假 k8s 客户端的 handler
+func forEachStorage(t *testing.T, fn func(t *testing.T, ph *Podhandler)) {
+	for _, driver := range []string{model.StorageMemory, model.StorageSqlite} {
+		driver := driver
+		t.Run(driver, func(t *testing.T) {
+			storage, err := model.OpenStorage(driver, filepath.Join(t.TempDir(), "pod.db"), nil)
+			if err != nil {
+				t.Fatal(err)
+			}
+			t.Cleanup(func() { storage.Close() })
+			if migrator := storage.Migrator(); migrator != nil {
+				if _, err := migrator.Up(0); err != nil {
+					t.Fatal(err)
+				}
+			}
+			podService := service.NewPodService(storage.Pods, storage.Revisions, storage.Quotas, fake.NewSimpleClientset())
+			fn(t, &Podhandler{
+				PodService: podService,
+				PodSaga:    service.NewPodSaga(podService),
+			})
+		})
+	}
+}
+
+// 创建 pod 并返回查询结果
+func addTestPod(t *testing.T, ph *Podhandler) *pod.PodInfo {
+	t.Helper()
+	ctx := context.TODO()
+	if err := ph.AddPod(ctx, servicetest.PodInfo(), &pod.Response{}); err != nil {
+		t.Fatalf("AddPod: %v", err)
+	}
+	all := &pod.AllPod{}
+	if err := ph.FindAllPod(ctx, &pod.FindAll{}, all); err != nil {
+		t.Fatal(err)
+	}
+	if len(all.PodInfo) != 1 {
+		t.Fatalf("FindAllPod 返回 %d 个 pod, want 1", len(all.PodInfo))
+	}
+	info := &pod.PodInfo{}
+	if err := ph.FindPodById(ctx, &pod.PodId{Id: all.PodInfo[0].PodId}, info); err != nil {
+		t.Fatal(err)
+	}
+	return info
+}
+
+func TestAddAndFindPod(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, ph *Podhandler) {
+		info := addTestPod(t, ph)
+		if info.Version != 1 || info.Image != "nginx:1.23" {
+			t.Errorf("version = %d image = %s, want 1 nginx:1.23", info.Version, info.Image)
+		}
+		for _, env := range info.PodEnvs {
+			if env.EnvKey == "TOKEN" && env.EnvValue != service.SecretMask {
+				t.Errorf("secret 环境变量 = %q, want 掩码", env.EnvValue)
+			}
+		}
+		//同名 pod 已存在
+		rsp := &pod.Response{}
+		if err := ph.AddPod(context.TODO(), servicetest.PodInfo(), rsp); err == nil || rsp.Code != service.CodeConflict {
+			t.Errorf("code = %s, want CONFLICT", rsp.Code)
+		}
+		//其他命名空间中没有同名工作负载，写库时名称冲突
+		rsp = &pod.Response{}
+		other := servicetest.PodInfo()
+		other.PodNamespace = "other"
+		if err := ph.AddPod(context.TODO(), other, rsp); err == nil || rsp.Code != service.CodeConflict {
+			t.Errorf("code = %s, want CONFLICT", rsp.Code)
+		}
+		if !strings.HasPrefix(rsp.Msg, "记录已存在") || strings.Count(rsp.Msg, "记录已存在") != 1 {
+			t.Errorf("msg = %q, want 只有一个 记录已存在 前缀", rsp.Msg)
+		}
+	})
+}
+
+func TestPatchAndUpdatePod(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, ph *Podhandler) {
+		ctx := context.TODO()
+		info := addTestPod(t, ph)
+		if err := ph.PatchPod(ctx, &pod.PatchPodRequest{
+			PodId:      info.PodId,
+			Version:    info.Version,
+			Pod:        &pod.PodInfo{Image: "nginx:1.24"},
+			UpdateMask: &fieldmaskpb.FieldMask{Paths: []string{"image"}},
+		}, &pod.Response{}); err != nil {
+			t.Fatalf("PatchPod: %v", err)
+		}
+		patched := &pod.PodInfo{}
+		if err := ph.FindPodById(ctx, &pod.PodId{Id: info.PodId}, patched); err != nil {
+			t.Fatal(err)
+		}
+		if patched.Image != "nginx:1.24" || patched.Version != 2 || len(patched.PodEnvs) != 2 {
+			t.Errorf("image = %s version = %d envs = %d, want nginx:1.24 2 2", patched.Image, patched.Version, len(patched.PodEnvs))
+		}
+		//基于旧版本的更新
+		rsp := &pod.Response{}
+		info.Image = "nginx:1.25"
+		if err := ph.UpdatePod(ctx, info, rsp); err == nil || rsp.Code != service.CodeConflict || rsp.Detail != "version:2" {
+			t.Errorf("code = %s detail = %s, want CONFLICT version:2", rsp.Code, rsp.Detail)
+		}
+		//没有填写版本
+		rsp = &pod.Response{}
+		info.Version = 0
+		if err := ph.UpdatePod(ctx, info, rsp); err == nil || rsp.Code != service.CodeInvalidArgument {
+			t.Errorf("code = %s, want INVALID_ARGUMENT", rsp.Code)
+		}
+	})
+}
+
+func TestRollbackAndDeletePod(t *testing.T) {
+	forEachStorage(t, func(t *testing.T, ph *Podhandler) {
+		ctx := context.TODO()
+		info := addTestPod(t, ph)
+		info.Image = "nginx:1.24"
+		if err := ph.UpdatePod(ctx, info, &pod.Response{}); err != nil {
+			t.Fatalf("UpdatePod: %v", err)
+		}
+		if err := ph.RollbackPod(ctx, &pod.RollbackRequest{PodId: info.PodId, Revision: 1}, &pod.Response{}); err != nil {
+			t.Fatalf("RollbackPod: %v", err)
+		}
+		revisions := &pod.PodRevisions{}
+		if err := ph.ListPodRevisions(ctx, &pod.PodId{Id: info.PodId}, revisions); err != nil {
+			t.Fatal(err)
+		}
+		if len(revisions.Revisions) != 3 {
+			t.Errorf("%d 个版本, want 3", len(revisions.Revisions))
+		}
+		current := &pod.PodInfo{}
+		if err := ph.FindPodById(ctx, &pod.PodId{Id: info.PodId}, current); err != nil {
+			t.Fatal(err)
+		}
+		if current.Image != "nginx:1.23" {
+			t.Errorf("回滚后 image = %s, want nginx:1.23", current.Image)
+		}
+		if err := ph.DeletePod(ctx, &pod.PodInfo{PodId: info.PodId}, &pod.Response{}); err != nil {
+			t.Fatalf("DeletePod: %v", err)
+		}
+		if err := ph.FindPodById(ctx, &pod.PodId{Id: info.PodId}, &pod.PodInfo{}); err == nil {
+			t.Error("删除后仍然能查询到 pod")
+		}
+	})
+}
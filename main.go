@@ -1,99 +1,240 @@
 package main
 
 import (
+	"errors"
 	"flag"
+	"fmt"
 	"log"
-	"path"
+	"os"
+	"strconv"
 	"time"
 
 	"github.com/asim/go-micro/v3"
 	"github.com/asim/go-micro/v3/registry"
+	"github.com/asim/go-micro/v3/server"
 	"github.com/go-micro/plugins/v3/registry/consul"
 	"github.com/go-micro/plugins/v3/wrapper/breaker/hystrix"
 	limiter "github.com/go-micro/plugins/v3/wrapper/ratelimiter/uber"
 	opentracing2 "github.com/go-micro/plugins/v3/wrapper/trace/opentracing"
 	_ "github.com/go-sql-driver/mysql"
 	"github.com/jary-287/gopass-common/common"
+	"github.com/jary-287/gopass-pod/auth"
+	"github.com/jary-287/gopass-pod/config"
 	"github.com/jary-287/gopass-pod/handle"
 	"github.com/jary-287/gopass-pod/model"
 	"github.com/jary-287/gopass-pod/proto/pod"
 	"github.com/jary-287/gopass-pod/service"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/tools/clientcmd"
-	"k8s.io/client-go/util/homedir"
-)
-
-const (
-	// 注册中心
-	consulHost string = "192.168.0.19"
-	consulPort int64  = 8500
-	//链路追踪
-	tracerHost string = "localhost"
-	tracerPort int64  = 9092
-	// 熔断
-	hystrixPort int64 = 9091
-	// 监控
-	prometheusPort int64 = 9093
 )
 
 func main() {
-	var kubeconfig *string
-	if home := homedir.HomeDir(); home != "" {
-		kubeconfig = flag.String("kubeconfig", path.Join(home, ".kube", "config"), "kubeconfig 位置")
+	cfg, err := config.Load(os.Args[1:])
+	if err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
+		}
+		log.Fatal("配置加载失败: ", err)
+	}
+	//敏感字段加密
+	var encryptor model.Encryptor = model.NoopEncryptor{}
+	if cfg.KeyFile != "" {
+		keys, err := model.LoadKeyFile(cfg.KeyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		//reencrypt 需要读取加密之前写入的明文
+		keys.AllowPlaintext = cfg.AllowPlaintext || (len(cfg.Args) > 0 && cfg.Args[0] == "reencrypt")
+		encryptor = keys
 	} else {
-		kubeconfig = flag.String("kubeconfig", "", "kubeconfig 位置")
+		log.Println("未配置密钥文件,敏感字段不加密")
+	}
+	//子命令
+	if len(cfg.Args) > 0 && cfg.Args[0] == "reencrypt" {
+		reencrypt(cfg, encryptor)
+		return
+	}
+	if len(cfg.Args) > 0 && cfg.Args[0] == "migrate" {
+		migrateSchema(cfg, cfg.Args[1:])
+		return
 	}
-	flag.Parse()
 	//创建config实例
-	config, err := clientcmd.BuildConfigFromFlags("", *kubeconfig)
+	kubeConfig, err := clientcmd.BuildConfigFromFlags("", cfg.Kubeconfig)
 	if err != nil {
 		log.Fatal(err)
 	}
-	client, err := kubernetes.NewForConfig(config)
+	client, err := kubernetes.NewForConfig(kubeConfig)
 	if err != nil {
 		log.Fatal(err)
 	}
 	//注册中心
 	consulRegister := consul.NewRegistry(func(o *registry.Options) {
-		o.Addrs = []string{"192.168.0.19:8500"}
-		o.Timeout = 20 * time.Second
+		o.Addrs = []string{cfg.Registry.Address}
+		o.Timeout = cfg.Registry.Timeout
 
 	})
-	t, io, err := common.NewTracer("service.pod", ":9333")
-	if err != nil {
-		log.Fatal(err)
-	}
-
-	defer io.Close()
-	// 创建pod服务
-	serv := micro.NewService(
-		micro.Name("service.pod"),
-		micro.Version("latest"),
+	options := []micro.Option{
+		micro.Name(cfg.Service.Name),
+		micro.Version(cfg.Service.Version),
 		//注册中心
-		micro.Address(":8888"),
+		micro.Address(cfg.Service.Address),
 		micro.Registry(consulRegister),
-		//链路追踪
-		micro.WrapHandler(opentracing2.NewHandlerWrapper(t)),
-		micro.WrapClient(opentracing2.NewClientWrapper(t)),
+	}
+	//链路追踪
+	if cfg.Tracer.Address != "" {
+		t, io, err := common.NewTracer(cfg.Service.Name, cfg.Tracer.Address)
+		if err != nil {
+			log.Fatal(err)
+		}
+		defer io.Close()
+		options = append(options,
+			micro.WrapHandler(opentracing2.NewHandlerWrapper(t)),
+			micro.WrapClient(opentracing2.NewClientWrapper(t)),
+		)
+	} else {
+		log.Println("未配置 tracer 地址,不开启链路追踪")
+	}
+	options = append(options,
 		//熔断
 		micro.WrapClient(hystrix.NewClientWrapper()),
-		micro.WrapHandler(limiter.NewHandlerWrapper(1000)),
+		micro.WrapHandler(limiter.NewHandlerWrapper(cfg.Service.RateLimit)),
 	)
-	serv.Init()
+	// 创建pod服务
+	serv := micro.NewService(options...)
+	//flag 已经由 config 包解析，不再交给 go-micro 解析命令行
 	// 初始化数据表
-	err = model.GetDB()
+	storage, err := openStorage(cfg, encryptor)
 	if err != nil {
 		log.Fatal("数据库初始化失败", err)
 	}
-	if err := model.NewPodRegistry(model.Db).InitTable(); err != nil {
-		log.Fatal(err)
+	defer storage.Close()
+	if migrator := storage.Migrator(); migrator != nil {
+		if cfg.Storage.AutoMigrate {
+			_, err = migrator.Up(0)
+		} else {
+			err = migrator.Check()
+		}
+		if err != nil {
+			log.Fatal("数据库迁移失败: ", err)
+		}
+	}
+
+	//调和 pod 表与集群
+	if cfg.Reconcile.Interval > 0 {
+		reconciler := service.NewPodReconciler(storage.Pods, client, cfg.Reconcile.Interval, cfg.Reconcile.DryRun)
+		stopCh := make(chan struct{})
+		defer close(stopCh)
+		go reconciler.Run(stopCh)
 	}
 
 	//注册句柄
-	podService := service.NewPodService(model.NewPodRegistry(model.Db), client)
-	pod.RegisterPodHandler(serv.Server(), &handle.Podhandler{PodService: podService})
+	podService := service.NewPodService(storage.Pods, storage.Revisions, storage.Quotas, client)
+	podHandler := &handle.Podhandler{
+		PodService: podService,
+		PodSaga:    service.NewPodSaga(podService),
+	}
+	//团队鉴权，policy 依赖数据库，在这里才能加入
+	if cfg.JwtKeyFile != "" {
+		verifier, err := auth.LoadKeyFile(cfg.JwtKeyFile)
+		if err != nil {
+			log.Fatal(err)
+		}
+		serv.Server().Init(server.WrapHandler(auth.NewHandlerWrapper(verifier, podHandler.Authorize)))
+	} else {
+		log.Println("未配置 jwt 密钥文件,不校验调用方身份")
+	}
+	pod.RegisterPodHandler(serv.Server(), podHandler)
 
 	if err := serv.Run(); err != nil {
 		log.Fatal(err)
 	}
 }
+
+// 轮换密钥后用新的主密钥重新加密所有行
+func reencrypt(cfg *config.Config, encryptor model.Encryptor) {
+	if _, ok := encryptor.(model.NoopEncryptor); ok {
+		log.Fatal("reencrypt 需要通过 -key-file 指定密钥文件")
+	}
+	storage, err := openStorage(cfg, encryptor)
+	if err != nil {
+		log.Fatal("数据库初始化失败", err)
+	}
+	defer storage.Close()
+	pods, ok := storage.Pods.(model.Reencrypter)
+	if !ok {
+		log.Fatalf("%s 存储不加密保存数据,不需要重新加密", storage.Driver)
+	}
+	envs, err := pods.Reencrypt()
+	if err != nil {
+		log.Fatal("pod_env 重新加密失败: ", err)
+	}
+	revisions, err := storage.Revisions.(model.Reencrypter).Reencrypt()
+	if err != nil {
+		log.Fatal("pod_revision 重新加密失败: ", err)
+	}
+	log.Printf("重新加密完成,pod_env %d 行,pod_revision %d 行", envs, revisions)
+}
+
+// 执行表结构迁移，migrate up [版本号] | down [回滚个数,默认 1] | status
+func migrateSchema(cfg *config.Config, args []string) {
+	storage, err := openStorage(cfg, model.NoopEncryptor{})
+	if err != nil {
+		log.Fatal("数据库初始化失败", err)
+	}
+	defer storage.Close()
+	migrator := storage.Migrator()
+	if migrator == nil {
+		log.Fatalf("%s 存储没有表结构,不需要迁移", storage.Driver)
+	}
+	if len(args) == 0 {
+		log.Fatal("用法: migrate up [版本号] | down [回滚个数] | status")
+	}
+	//可选的数字参数
+	number := func(defaultValue int64) int64 {
+		if len(args) < 2 {
+			return defaultValue
+		}
+		value, err := strconv.ParseInt(args[1], 10, 64)
+		if err != nil || value <= 0 {
+			log.Fatalf("%s 的参数必须是正整数: %s", args[0], args[1])
+		}
+		return value
+	}
+	switch args[0] {
+	case "up":
+		done, err := migrator.Up(number(0))
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("迁移完成,执行 %d 个迁移", len(done))
+	case "down":
+		done, err := migrator.Down(int(number(1)))
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("回滚完成,回滚 %d 个迁移", len(done))
+	case "status":
+		statuses, err := migrator.Status()
+		if err != nil {
+			log.Fatal(err)
+		}
+		for _, status := range statuses {
+			appliedAt := "pending"
+			if status.Applied {
+				appliedAt = status.AppliedAt.Format(time.RFC3339)
+			}
+			fmt.Printf("%d\t%s\t%s\n", status.Version, status.Name, appliedAt)
+		}
+	default:
+		log.Fatalf("不支持的 migrate 子命令: %s,可选值 up/down/status", args[0])
+	}
+}
+
+// 按配置打开存储
+func openStorage(cfg *config.Config, encryptor model.Encryptor) (*model.Storage, error) {
+	dsn, err := cfg.StorageDSN()
+	if err != nil {
+		return nil, err
+	}
+	return model.OpenStorage(cfg.Storage.Driver, dsn, encryptor)
+}
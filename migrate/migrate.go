@@ -0,0 +1,276 @@
+package migrate
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// 锁的等待时间和过期时间，持有锁的进程异常退出后，超过过期时间的锁可以被其他进程抢占
+const (
+	lockTimeout = 2 * time.Minute
+	lockTTL     = 10 * time.Minute
+	lockRetry   = time.Second
+)
+
+// ErrPending 表示数据库中还有没有执行的迁移
+var ErrPending = errors.New("数据库中还有没有执行的迁移")
+
+// Migration 是一个版本的表结构变更，版本号递增且不能修改已经发布的迁移
+type Migration struct {
+	Version int64
+	Name    string
+	Up      func(tx *gorm.DB) error
+	//为 nil 时不支持回滚
+	Down func(tx *gorm.DB) error
+}
+
+// Status 是一个迁移的执行情况
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt *time.Time
+}
+
+// 已执行的迁移
+type schemaVersion struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaVersion) TableName() string {
+	return "schema_version"
+}
+
+// 迁移锁，只有一行，写入成功的进程持有锁
+type schemaLock struct {
+	ID       int64 `gorm:"primaryKey;autoIncrement:false"`
+	Owner    string
+	LockedAt time.Time
+}
+
+func (schemaLock) TableName() string {
+	return "schema_lock"
+}
+
+// mysql 和 sqlite 都支持的建表语句，多个副本同时启动时不会因为表已存在而失败
+var bookkeepingTables = []string{
+	"CREATE TABLE IF NOT EXISTS schema_version (version BIGINT NOT NULL PRIMARY KEY, name VARCHAR(255) NOT NULL, applied_at DATETIME NOT NULL)",
+	"CREATE TABLE IF NOT EXISTS schema_lock (id BIGINT NOT NULL PRIMARY KEY, owner VARCHAR(255) NOT NULL, locked_at DATETIME NOT NULL)",
+}
+
+type Migrator struct {
+	db         *gorm.DB
+	migrations []Migration
+	owner      string
+}
+
+// New 返回按版本号排序执行 migrations 的迁移器
+func New(db *gorm.DB, migrations []Migration) *Migrator {
+	sorted := make([]Migration, len(migrations))
+	copy(sorted, migrations)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Version < sorted[j].Version })
+	host, _ := os.Hostname()
+	return &Migrator{
+		db:         db,
+		migrations: sorted,
+		owner:      fmt.Sprintf("%s:%d", host, os.Getpid()),
+	}
+}
+
+// Up 执行版本号不大于 target 的所有未执行迁移，target 为 0 时执行到最新，返回执行的迁移
+func (m *Migrator) Up(target int64) (done []Migration, err error) {
+	err = m.withLock(func(applied map[int64]schemaVersion) error {
+		for _, migration := range m.migrations {
+			if target > 0 && migration.Version > target {
+				break
+			}
+			if _, ok := applied[migration.Version]; ok {
+				continue
+			}
+			log.Printf("执行迁移 %d_%s", migration.Version, migration.Name)
+			//mysql 的 DDL 会隐式提交，失败时只能回滚同一个迁移中的数据变更
+			err := m.db.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Up(tx); err != nil {
+					return err
+				}
+				return tx.Create(&schemaVersion{
+					Version:   migration.Version,
+					Name:      migration.Name,
+					AppliedAt: time.Now(),
+				}).Error
+			})
+			if err != nil {
+				return fmt.Errorf("迁移 %d_%s 失败: %v", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Down 按版本号从大到小回滚 steps 个已执行的迁移，返回回滚的迁移
+func (m *Migrator) Down(steps int) (done []Migration, err error) {
+	err = m.withLock(func(applied map[int64]schemaVersion) error {
+		for i := len(m.migrations) - 1; i >= 0 && len(done) < steps; i-- {
+			migration := m.migrations[i]
+			if _, ok := applied[migration.Version]; !ok {
+				continue
+			}
+			if migration.Down == nil {
+				return fmt.Errorf("迁移 %d_%s 不支持回滚", migration.Version, migration.Name)
+			}
+			log.Printf("回滚迁移 %d_%s", migration.Version, migration.Name)
+			err := m.db.Transaction(func(tx *gorm.DB) error {
+				if err := migration.Down(tx); err != nil {
+					return err
+				}
+				return tx.Delete(&schemaVersion{}, migration.Version).Error
+			})
+			if err != nil {
+				return fmt.Errorf("回滚迁移 %d_%s 失败: %v", migration.Version, migration.Name, err)
+			}
+			done = append(done, migration)
+		}
+		return nil
+	})
+	return
+}
+
+// Status 返回所有迁移的执行情况，数据库中有但代码中没有的版本也会列出
+func (m *Migrator) Status() ([]Status, error) {
+	if err := m.createTables(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var result []Status
+	for _, migration := range m.migrations {
+		status := Status{Version: migration.Version, Name: migration.Name}
+		if version, ok := applied[migration.Version]; ok {
+			status.Applied = true
+			status.AppliedAt = &version.AppliedAt
+			delete(applied, migration.Version)
+		}
+		result = append(result, status)
+	}
+	//由更新版本的程序执行的迁移
+	for _, version := range applied {
+		version := version
+		result = append(result, Status{Version: version.Version, Name: version.Name, Applied: true, AppliedAt: &version.AppliedAt})
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Version < result[j].Version })
+	return result, nil
+}
+
+// Pending 返回还没有执行的迁移
+func (m *Migrator) Pending() ([]Migration, error) {
+	if err := m.createTables(); err != nil {
+		return nil, err
+	}
+	applied, err := m.applied()
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, migration := range m.migrations {
+		if _, ok := applied[migration.Version]; !ok {
+			pending = append(pending, migration)
+		}
+	}
+	return pending, nil
+}
+
+func (m *Migrator) createTables() error {
+	for _, sql := range bookkeepingTables {
+		if err := m.db.Exec(sql).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (m *Migrator) applied() (map[int64]schemaVersion, error) {
+	var versions []schemaVersion
+	if err := m.db.Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	applied := make(map[int64]schemaVersion, len(versions))
+	for _, version := range versions {
+		applied[version.Version] = version
+	}
+	return applied, nil
+}
+
+// 持有迁移锁执行 fn，多个副本同时启动时只有一个执行迁移，其他副本等待后看到已执行的版本
+func (m *Migrator) withLock(fn func(applied map[int64]schemaVersion) error) error {
+	if err := m.createTables(); err != nil {
+		return err
+	}
+	if err := m.lock(); err != nil {
+		return err
+	}
+	defer m.unlock()
+	applied, err := m.applied()
+	if err != nil {
+		return err
+	}
+	return fn(applied)
+}
+
+func (m *Migrator) lock() error {
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		//清理异常退出的进程留下的锁
+		if err := m.db.Where("id = 1 AND locked_at < ?", time.Now().Add(-lockTTL)).Delete(&schemaLock{}).Error; err != nil {
+			return err
+		}
+		//锁被占用时主键冲突是正常情况，不打印错误日志
+		quiet := m.db.Session(&gorm.Session{Logger: m.db.Logger.LogMode(logger.Silent)})
+		err := quiet.Create(&schemaLock{ID: 1, Owner: m.owner, LockedAt: time.Now()}).Error
+		if err == nil {
+			return nil
+		}
+		var current []schemaLock
+		if findErr := m.db.Where("id = 1").Limit(1).Find(&current).Error; findErr != nil || len(current) == 0 {
+			return fmt.Errorf("获取迁移锁失败: %v", err)
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("等待迁移锁超时,锁由 %s 在 %s 持有", current[0].Owner, current[0].LockedAt.Format(time.RFC3339))
+		}
+		log.Printf("迁移锁由 %s 持有,等待释放", current[0].Owner)
+		time.Sleep(lockRetry)
+	}
+}
+
+func (m *Migrator) unlock() {
+	result := m.db.Where("id = 1 AND owner = ?", m.owner).Delete(&schemaLock{})
+	if result.Error != nil {
+		log.Println("释放迁移锁失败:", result.Error)
+	} else if result.RowsAffected == 0 {
+		log.Println("迁移锁已经过期,被其他进程清理")
+	}
+}
+
+// Check 在不自动迁移时检查数据库是否已经是最新版本
+func (m *Migrator) Check() error {
+	pending, err := m.Pending()
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: 从 %d_%s 开始共 %d 个,请先执行 migrate up", ErrPending, pending[0].Version, pending[0].Name, len(pending))
+	}
+	return nil
+}
@@ -0,0 +1,248 @@
+package migrate
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "pod.db")), &gorm.Config{
+		DisableForeignKeyConstraintWhenMigrating: true,
+		NamingStrategy:                           schema.NamingStrategy{SingularTable: true},
+		Logger:                                   logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		t.Fatal(err)
+	}
+	//sqlite 只支持一个写连接
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return db
+}
+
+func versions(migrations []Migration) (result []int64) {
+	for _, migration := range migrations {
+		result = append(result, migration.Version)
+	}
+	return
+}
+
+func TestUpFromEmpty(t *testing.T) {
+	db := openTestDB(t)
+	migrator := New(db, Migrations)
+	done, err := migrator.Up(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != len(Migrations) {
+		t.Fatalf("执行了 %v, want 全部 %d 个", versions(done), len(Migrations))
+	}
+	for _, table := range []string{"pod", "pod_env", "pod_port", "pod_revision", "team_quota"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("没有创建表 %s", table)
+		}
+	}
+	if !db.Migrator().HasColumn(&podVersionV3{}, "Version") {
+		t.Error("pod 表没有 version 列")
+	}
+	//再次执行不会重复迁移
+	if done, err := migrator.Up(0); err != nil || len(done) != 0 {
+		t.Errorf("再次执行了 %v, %v, want 无", versions(done), err)
+	}
+	if err := migrator.Check(); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+}
+
+func TestUpTarget(t *testing.T) {
+	migrator := New(openTestDB(t), Migrations)
+	done, err := migrator.Up(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != 1 {
+		t.Fatalf("执行了 %v, want [1]", versions(done))
+	}
+	if err := migrator.Check(); !errors.Is(err, ErrPending) {
+		t.Errorf("Check = %v, want ErrPending", err)
+	}
+	pending, err := migrator.Pending()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pending) != len(Migrations)-1 {
+		t.Errorf("pending = %v, want %d 个", versions(pending), len(Migrations)-1)
+	}
+}
+
+// 引入迁移之前由 AutoMigrate 建的表，环境变量和端口没有容器名称
+func TestUpFromPreBaseline(t *testing.T) {
+	db := openTestDB(t)
+	for _, sql := range []string{
+		"CREATE TABLE pod (pod_id INTEGER PRIMARY KEY, pod_name TEXT NOT NULL UNIQUE, pod_name_space TEXT, image TEXT NOT NULL)",
+		"CREATE TABLE pod_env (id INTEGER PRIMARY KEY AUTOINCREMENT, pod_id INTEGER, env_key TEXT, env_value TEXT)",
+		"CREATE TABLE pod_port (id INTEGER PRIMARY KEY AUTOINCREMENT, pod_id INTEGER, port INTEGER, protocol TEXT)",
+		"INSERT INTO pod (pod_id, pod_name, pod_name_space, image) VALUES (1, 'web', 'default', 'nginx')",
+		"INSERT INTO pod_env (pod_id, env_key, env_value) VALUES (1, 'MODE', 'prod')",
+		"INSERT INTO pod_port (pod_id, port, protocol) VALUES (1, 80, 'TCP')",
+	} {
+		if err := db.Exec(sql).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := New(db, Migrations).Up(0); err != nil {
+		t.Fatal(err)
+	}
+	for _, table := range []string{"pod_env", "pod_port"} {
+		var containerName string
+		if err := db.Table(table).Select("container_name").Where("pod_id = 1").Scan(&containerName).Error; err != nil {
+			t.Fatal(err)
+		}
+		if containerName != "web" {
+			t.Errorf("%s.container_name = %q, want 回填为 web", table, containerName)
+		}
+	}
+	var version int64
+	if err := db.Table("pod").Select("version").Where("pod_id = 1").Scan(&version).Error; err != nil {
+		t.Fatal(err)
+	}
+	if version != 1 {
+		t.Errorf("已有 pod 的 version = %d, want 1", version)
+	}
+}
+
+func TestDownAndUp(t *testing.T) {
+	db := openTestDB(t)
+	migrator := New(db, Migrations)
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	last := Migrations[len(Migrations)-1]
+	done, err := migrator.Down(1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != last.Version {
+		t.Fatalf("回滚了 %v, want [%d]", versions(done), last.Version)
+	}
+	if db.Migrator().HasColumn(&podVersionV3{}, "Version") {
+		t.Error("回滚后 pod 表仍然有 version 列")
+	}
+	if err := migrator.Check(); !errors.Is(err, ErrPending) {
+		t.Errorf("Check = %v, want ErrPending", err)
+	}
+	done, err = migrator.Up(0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 1 || done[0].Version != last.Version {
+		t.Fatalf("重新执行了 %v, want [%d]", versions(done), last.Version)
+	}
+	if !db.Migrator().HasColumn(&podVersionV3{}, "Version") {
+		t.Error("重新执行后 pod 表没有 version 列")
+	}
+}
+
+func TestStatus(t *testing.T) {
+	db := openTestDB(t)
+	if _, err := New(db, Migrations).Up(2); err != nil {
+		t.Fatal(err)
+	}
+	//更新版本的程序执行过的迁移
+	if err := db.Create(&schemaVersion{Version: 99, Name: "future", AppliedAt: time.Now()}).Error; err != nil {
+		t.Fatal(err)
+	}
+	status, err := New(db, Migrations).Status()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []struct {
+		version int64
+		applied bool
+	}{{1, true}, {2, true}, {3, false}, {99, true}}
+	if len(status) != len(want) {
+		t.Fatalf("status = %+v, want %d 个", status, len(want))
+	}
+	for i, w := range want {
+		if status[i].Version != w.version || status[i].Applied != w.applied || (status[i].AppliedAt != nil) != w.applied {
+			t.Errorf("status[%d] = %+v, want version %d applied %v", i, status[i], w.version, w.applied)
+		}
+	}
+}
+
+// 其他进程持有锁时等待锁释放后再执行
+func TestLockBlocksConcurrentMigrator(t *testing.T) {
+	db := openTestDB(t)
+	migrator := New(db, Migrations)
+	if err := migrator.createTables(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&schemaLock{ID: 1, Owner: "other", LockedAt: time.Now()}).Error; err != nil {
+		t.Fatal(err)
+	}
+	result := make(chan error, 1)
+	go func() {
+		_, err := migrator.Up(0)
+		result <- err
+	}()
+	select {
+	case err := <-result:
+		t.Fatalf("锁被占用时 Up 返回了 %v, want 等待", err)
+	case <-time.After(lockRetry / 2):
+	}
+	var applied int64
+	if err := db.Model(&schemaVersion{}).Count(&applied).Error; err != nil {
+		t.Fatal(err)
+	}
+	if applied != 0 {
+		t.Fatalf("等待锁时执行了 %d 个迁移", applied)
+	}
+	if err := db.Where("id = 1 AND owner = ?", "other").Delete(&schemaLock{}).Error; err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-result:
+		if err != nil {
+			t.Fatal(err)
+		}
+	case <-time.After(5 * lockRetry):
+		t.Fatal("锁释放后 Up 没有继续执行")
+	}
+	if err := migrator.Check(); err != nil {
+		t.Errorf("Check: %v", err)
+	}
+}
+
+// 超过过期时间的锁视为持有者已经退出
+func TestStaleLockIsTakenOver(t *testing.T) {
+	db := openTestDB(t)
+	migrator := New(db, Migrations)
+	if err := migrator.createTables(); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Create(&schemaLock{ID: 1, Owner: "crashed", LockedAt: time.Now().Add(-lockTTL - time.Minute)}).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrator.Up(0); err != nil {
+		t.Fatal(err)
+	}
+	var locks int64
+	if err := db.Model(&schemaLock{}).Count(&locks).Error; err != nil {
+		t.Fatal(err)
+	}
+	if locks != 0 {
+		t.Errorf("迁移完成后还有 %d 个锁", locks)
+	}
+}
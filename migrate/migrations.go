@@ -0,0 +1,203 @@
+package migrate
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Migrations 是 pod 服务所有的表结构迁移，新的变更追加到末尾，不要修改已经发布的迁移
+var Migrations = []Migration{
+	{
+		Version: 1,
+		Name:    "baseline",
+		Up: func(tx *gorm.DB) error {
+			//引入迁移之前由 AutoMigrate 建的表在这里被接管，已有的表只补齐缺少的列和索引
+			return tx.Migrator().AutoMigrate(baselineTables...)
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropTable(baselineTables...)
+		},
+	},
+	{
+		Version: 2,
+		Name:    "backfill_container_name",
+		Up:      backfillContainerName,
+		//回填的容器名称与多容器之前的含义相同，回滚时不需要清除
+		Down: func(tx *gorm.DB) error { return nil },
+	},
+	{
+		Version: 3,
+		Name:    "pod_version",
+		//已有的 pod 从版本 1 开始
+		Up: func(tx *gorm.DB) error {
+			if tx.Migrator().HasColumn(&podVersionV3{}, "Version") {
+				return nil
+			}
+			return tx.Migrator().AddColumn(&podVersionV3{}, "Version")
+		},
+		Down: func(tx *gorm.DB) error {
+			return tx.Migrator().DropColumn(&podVersionV3{}, "Version")
+		},
+	},
+}
+
+// 单容器时期写入的环境变量和端口没有容器名称，归到主容器
+func backfillContainerName(tx *gorm.DB) error {
+	for _, table := range []string{"pod_env", "pod_port"} {
+		result := tx.Table(table).Where("container_name = ? OR container_name IS NULL", "").
+			Update("container_name", gorm.Expr("(SELECT pod_name FROM pod WHERE pod.pod_id = "+table+".pod_id)"))
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected > 0 {
+			log.Printf("%s 表迁移到多容器结构,%d 行", table, result.RowsAffected)
+		}
+	}
+	return nil
+}
+
+// 以下是版本 1 时的表结构快照，model 中的结构体之后的变化通过新的迁移完成，这里不要跟着修改
+// json 序列化的字段在数据库中是字符串
+
+var baselineTables = []interface{}{
+	&podV1{}, &podEnvV1{}, &podPortV1{}, &podProbeV1{}, &podVolumeV1{}, &podContainerV1{},
+	&podRevisionV1{}, &teamQuotaV1{},
+}
+
+type podV1 struct {
+	PodID              uint64 `gorm:"primaryKey;not null"`
+	PodName            string `gorm:"unique;not null"`
+	PodNameSpace       string `gorm:"index:idx_pod_namespace"`
+	PodTeamID          int64  `gorm:"index:idx_pod_team_id"`
+	PodMaxCpuUsage     float64
+	PodMinCpuUsage     float64
+	PodMaxMemUsage     float64
+	PodMinMemUsage     float64
+	Image              string `gorm:"not null"`
+	PodPullPolicy      string `gorm:"default:'if_not_present'"`
+	PodRestartPolicy   string `gorm:"default:'always'"`
+	PodDeployType      string `gorm:"index:idx_pod_deploy_type"`
+	Replicas           int32
+	PodStorageSize     string
+	PodStorageClass    string
+	PodStoragePath     string
+	PodServiceType     string `gorm:"default:'cluster_ip'"`
+	PodCommand         string
+	PodArgs            string
+	PodWorkingDir      string
+	PodSecurityContext string
+}
+
+func (podV1) TableName() string { return "pod" }
+
+type podEnvV1 struct {
+	ID            uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID         uint64 `gorm:"index"`
+	EnvKey        string
+	EnvValue      string
+	Secret        bool
+	ContainerName string
+}
+
+func (podEnvV1) TableName() string { return "pod_env" }
+
+type podPortV1 struct {
+	ID            uint   `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID         uint64 `gorm:"index"`
+	Port          int32
+	Protocol      string
+	ServicePort   int32
+	NodePort      int32
+	ContainerName string
+}
+
+func (podPortV1) TableName() string { return "pod_port" }
+
+type podProbeV1 struct {
+	ID                  uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID               uint64 `gorm:"index"`
+	ProbeType           string
+	Handler             string
+	Path                string
+	Port                int32
+	Scheme              string
+	Command             string
+	GrpcService         string
+	InitialDelaySeconds int32
+	PeriodSeconds       int32
+	TimeoutSeconds      int32
+	SuccessThreshold    int32
+	FailureThreshold    int32
+}
+
+func (podProbeV1) TableName() string { return "pod_probe" }
+
+type podVolumeV1 struct {
+	ID           uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID        uint64 `gorm:"index"`
+	Name         string
+	VolumeType   string
+	ClaimName    string
+	StorageSize  string
+	StorageClass string
+	AccessMode   string
+	Source       string
+	MountPath    string
+	SubPath      string
+	ReadOnly     bool
+}
+
+func (podVolumeV1) TableName() string { return "pod_volume" }
+
+type podContainerV1 struct {
+	ID              uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID           uint64 `gorm:"index"`
+	Name            string
+	Kind            string `gorm:"default:'container'"`
+	Image           string `gorm:"not null"`
+	PullPolicy      string
+	Command         string
+	Args            string
+	WorkingDir      string
+	MaxCpu          string
+	MinCpu          string
+	MaxMem          string
+	MinMem          string
+	VolumeMounts    string
+	SecurityContext string
+}
+
+func (podContainerV1) TableName() string { return "pod_container" }
+
+type podRevisionV1 struct {
+	ID        uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	PodID     uint64 `gorm:"uniqueIndex:idx_pod_revision;not null"`
+	Revision  int64  `gorm:"uniqueIndex:idx_pod_revision;not null"`
+	Spec      string `gorm:"type:mediumtext"`
+	Action    string
+	ChangedBy string
+	CreatedAt time.Time
+}
+
+func (podRevisionV1) TableName() string { return "pod_revision" }
+
+type teamQuotaV1 struct {
+	ID          uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT"`
+	TeamID      int64  `gorm:"uniqueIndex;not null"`
+	MaxCpu      float64
+	MaxMem      float64
+	MaxReplicas int64
+	MaxPods     int64
+	Namespaces  string
+}
+
+func (teamQuotaV1) TableName() string { return "team_quota" }
+
+// 版本 3 给 pod 表增加的乐观锁版本号
+type podVersionV3 struct {
+	Version int64 `gorm:"not null;default:1"`
+}
+
+func (podVersionV3) TableName() string { return "pod" }
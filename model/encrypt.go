@@ -0,0 +1,184 @@
+package model
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// 密文前缀，没有前缀的值是加密之前写入的明文
+const cipherPrefix = "enc:v1:"
+
+// Encryptor 加解密数据库中的敏感字段
+// context 标识值所在的表、行和字段，作为附加数据参与加密，密文被复制到其他行或字段后无法解密
+type Encryptor interface {
+	//加密，返回可以直接写入数据库的字符串
+	Encrypt(value, context string) (string, error)
+	//解密，context 必须与加密时相同
+	Decrypt(value, context string) (string, error)
+	//判断值是否需要用当前密钥重新加密
+	NeedsReencrypt(string) bool
+}
+
+// NoopEncryptor 不加密，未配置密钥文件时使用
+type NoopEncryptor struct{}
+
+func (NoopEncryptor) Encrypt(value, context string) (string, error) { return value, nil }
+
+func (NoopEncryptor) Decrypt(value, context string) (string, error) {
+	if strings.HasPrefix(value, cipherPrefix) {
+		return "", errors.New("数据已加密,但没有配置密钥文件")
+	}
+	return value, nil
+}
+
+func (NoopEncryptor) NeedsReencrypt(string) bool { return false }
+
+// KeyFile 是本地密钥文件的格式，keys 为 密钥id -> base64 编码的 32 字节密钥，新数据使用 primary 加密
+//
+//	{"primary": "k2", "keys": {"k1": "...", "k2": "..."}}
+//
+// 轮换密钥时加入新密钥并修改 primary，执行 reencrypt 之后才能删除旧密钥
+type KeyFile struct {
+	Primary string            `json:"primary"`
+	Keys    map[string]string `json:"keys"`
+}
+
+// AESGCMEncryptor 使用信封加密：每个值生成一个数据密钥，数据密钥再用密钥文件中的主密钥加密后和密文一起保存
+type AESGCMEncryptor struct {
+	primary string
+	keys    map[string]cipher.AEAD
+	//读取没有加密的旧数据，只在 reencrypt 完成之前的迁移期间开启，关闭时明文视为被篡改
+	AllowPlaintext bool
+}
+
+// LoadKeyFile 读取本地密钥文件并创建 AES-GCM 加密器
+func LoadKeyFile(path string) (*AESGCMEncryptor, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取密钥文件失败: %v", err)
+	}
+	keyFile := &KeyFile{}
+	if err := json.Unmarshal(data, keyFile); err != nil {
+		return nil, fmt.Errorf("密钥文件格式错误: %v", err)
+	}
+	return NewAESGCMEncryptor(keyFile)
+}
+
+func NewAESGCMEncryptor(keyFile *KeyFile) (*AESGCMEncryptor, error) {
+	if _, ok := keyFile.Keys[keyFile.Primary]; !ok {
+		return nil, fmt.Errorf("密钥文件中没有主密钥: %s", keyFile.Primary)
+	}
+	e := &AESGCMEncryptor{primary: keyFile.Primary, keys: map[string]cipher.AEAD{}}
+	for id, encoded := range keyFile.Keys {
+		if id == "" || strings.Contains(id, ":") {
+			return nil, fmt.Errorf("密钥 id 不合法: %q", id)
+		}
+		key, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("密钥 %s 必须是 base64 编码的 32 字节", id)
+		}
+		if e.keys[id], err = newGCM(key); err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
+
+// Encrypt 输出格式为 enc:v1:<密钥id>:<加密后的数据密钥>:<密文>
+func (e *AESGCMEncryptor) Encrypt(value, context string) (string, error) {
+	dataKey := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dataKey); err != nil {
+		return "", err
+	}
+	dataGCM, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	//数据密钥以密钥 id 作为附加数据，防止被替换到其他密钥下
+	wrappedKey, err := seal(e.keys[e.primary], dataKey, []byte(e.primary))
+	if err != nil {
+		return "", err
+	}
+	sealed, err := seal(dataGCM, []byte(value), []byte(context))
+	if err != nil {
+		return "", err
+	}
+	return cipherPrefix + e.primary + ":" +
+		base64.RawURLEncoding.EncodeToString(wrappedKey) + ":" +
+		base64.RawURLEncoding.EncodeToString(sealed), nil
+}
+
+func (e *AESGCMEncryptor) Decrypt(value, context string) (string, error) {
+	if !strings.HasPrefix(value, cipherPrefix) {
+		if e.AllowPlaintext {
+			return value, nil
+		}
+		return "", errors.New("数据没有加密,执行 reencrypt 之前需要开启 allow_plaintext")
+	}
+	parts := strings.Split(strings.TrimPrefix(value, cipherPrefix), ":")
+	if len(parts) != 3 {
+		return "", errors.New("密文格式错误")
+	}
+	keyGCM, ok := e.keys[parts[0]]
+	if !ok {
+		return "", fmt.Errorf("密钥文件中没有密钥: %s", parts[0])
+	}
+	wrappedKey, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", errors.New("密文格式错误")
+	}
+	sealed, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return "", errors.New("密文格式错误")
+	}
+	dataKey, err := open(keyGCM, wrappedKey, []byte(parts[0]))
+	if err != nil {
+		return "", fmt.Errorf("数据密钥解密失败: %v", err)
+	}
+	dataGCM, err := newGCM(dataKey)
+	if err != nil {
+		return "", err
+	}
+	plain, err := open(dataGCM, sealed, []byte(context))
+	if err != nil {
+		return "", fmt.Errorf("数据解密失败: %v", err)
+	}
+	return string(plain), nil
+}
+
+// NeedsReencrypt 明文或者不是用主密钥加密的值需要重新加密
+func (e *AESGCMEncryptor) NeedsReencrypt(value string) bool {
+	return !strings.HasPrefix(value, cipherPrefix+e.primary+":")
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// 随机 nonce 放在密文前面
+func seal(gcm cipher.AEAD, plain, additional []byte) ([]byte, error) {
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return gcm.Seal(nonce, nonce, plain, additional), nil
+}
+
+func open(gcm cipher.AEAD, sealed, additional []byte) ([]byte, error) {
+	if len(sealed) < gcm.NonceSize() {
+		return nil, errors.New("密文长度不足")
+	}
+	return gcm.Open(nil, sealed[:gcm.NonceSize()], sealed[gcm.NonceSize():], additional)
+}
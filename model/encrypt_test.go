@@ -0,0 +1,54 @@
+package model
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+)
+
+func testEncryptor(t *testing.T) *AESGCMEncryptor {
+	t.Helper()
+	encryptor, err := NewAESGCMEncryptor(&KeyFile{
+		Primary: "k1",
+		Keys:    map[string]string{"k1": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encryptor
+}
+
+func TestEncryptorContext(t *testing.T) {
+	encryptor := testEncryptor(t)
+	sealed, err := encryptor.Encrypt("value", "pod_env:1:web:TOKEN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(sealed, cipherPrefix+"k1:") {
+		t.Fatalf("密文 = %s, want 前缀 %sk1:", sealed, cipherPrefix)
+	}
+	plain, err := encryptor.Decrypt(sealed, "pod_env:1:web:TOKEN")
+	if err != nil || plain != "value" {
+		t.Fatalf("Decrypt = %q, %v, want value", plain, err)
+	}
+	//复制到其他行或其他变量的密文不能解密
+	for _, context := range []string{"pod_env:2:web:TOKEN", "pod_env:1:web:PASSWORD", ""} {
+		if _, err := encryptor.Decrypt(sealed, context); err == nil {
+			t.Errorf("context %q 解密成功, want 失败", context)
+		}
+	}
+}
+
+func TestEncryptorPlaintext(t *testing.T) {
+	encryptor := testEncryptor(t)
+	if _, err := encryptor.Decrypt("plain", "pod_env:1:web:TOKEN"); err == nil {
+		t.Error("没有开启 AllowPlaintext 时明文应该被拒绝")
+	}
+	encryptor.AllowPlaintext = true
+	if plain, err := encryptor.Decrypt("plain", "pod_env:1:web:TOKEN"); err != nil || plain != "plain" {
+		t.Errorf("Decrypt = %q, %v, want plain", plain, err)
+	}
+	if _, err := (NoopEncryptor{}).Decrypt(cipherPrefix+"k1:a:b", ""); err == nil {
+		t.Error("没有密钥时密文应该无法读取")
+	}
+}
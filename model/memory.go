@@ -0,0 +1,358 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// MemoryPodRegistry 把 pod 保存在内存中，用于本地运行和测试，进程退出后数据丢失
+type MemoryPodRegistry struct {
+	mu   sync.RWMutex
+	pods map[uint64]*Pod
+	//pod 和子表各自的自增 ID
+	lastPodID   uint64
+	lastChildID uint64
+}
+
+func NewMemoryPodRegistry() *MemoryPodRegistry {
+	return &MemoryPodRegistry{
+		pods: map[uint64]*Pod{},
+	}
+}
+
+func (m *MemoryPodRegistry) GetById(id uint64) (*Pod, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pod, ok := m.pods[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return clonePod(pod), nil
+}
+
+func (m *MemoryPodRegistry) CreatePod(pod *Pod) (uint64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if pod.PodID == 0 {
+		pod.PodID = m.lastPodID + 1
+	}
+	if _, ok := m.pods[pod.PodID]; ok {
+		return 0, fmt.Errorf("%w: pod id %d", ErrDuplicateKey, pod.PodID)
+	}
+	if err := m.checkName(pod); err != nil {
+		return 0, err
+	}
+	if pod.PodID > m.lastPodID {
+		m.lastPodID = pod.PodID
+	}
+	if pod.Version == 0 {
+		pod.Version = 1
+	}
+	m.assignChildIDs(pod)
+	m.pods[pod.PodID] = clonePod(pod)
+	return pod.PodID, nil
+}
+
+func (m *MemoryPodRegistry) DeletePod(id uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.pods, id)
+	return nil
+}
+
+// UpdatePod 整体替换 pod 和子表，与数据库一样按版本号条件更新
+func (m *MemoryPodRegistry) UpdatePod(pod *Pod) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	current, ok := m.pods[pod.PodID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+	if current.Version != pod.Version {
+		return &VersionConflictError{PodID: pod.PodID, Version: pod.Version, Current: current.Version}
+	}
+	if err := m.checkName(pod); err != nil {
+		return err
+	}
+	pod.Version++
+	m.assignChildIDs(pod)
+	m.pods[pod.PodID] = clonePod(pod)
+	return nil
+}
+
+func (m *MemoryPodRegistry) Get() ([]Pod, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	pods := make([]Pod, 0, len(m.pods))
+	for _, pod := range m.pods {
+		pods = append(pods, *clonePod(pod))
+	}
+	sort.Slice(pods, func(i, j int) bool { return pods[i].PodID < pods[j].PodID })
+	return pods, nil
+}
+
+func (m *MemoryPodRegistry) UpdateReplicas(id uint64, replicas int32) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if pod, ok := m.pods[id]; ok {
+		pod.Replicas = replicas
+		pod.Version++
+	}
+	return nil
+}
+
+// Find 与 PodRegistry.Find 的过滤、排序和分页规则相同
+func (m *MemoryPodRegistry) Find(query *PodQuery) ([]Pod, int64, error) {
+	orderBy := query.OrderBy
+	if orderBy == "" {
+		orderBy = "pod_id"
+	}
+	if _, ok := podOrderColumns[orderBy]; !ok {
+		return nil, 0, fmt.Errorf("不支持的排序字段: %s", query.OrderBy)
+	}
+	all, _ := m.Get()
+	var matched []Pod
+	for i := range all {
+		if matchPodQuery(&all[i], query) {
+			matched = append(matched, all[i])
+		}
+	}
+	//先按排序列再按 pod_id 排序
+	sort.SliceStable(matched, func(i, j int) bool {
+		result := compareCursor(matched[i].Cursor(orderBy), matched[j].Cursor(orderBy))
+		if query.Desc {
+			return result > 0
+		}
+		return result < 0
+	})
+	total := int64(len(matched))
+	if cursor := query.After; cursor != nil {
+		start := len(matched)
+		for i := range matched {
+			result := compareCursor(matched[i].Cursor(orderBy), cursor)
+			if (!query.Desc && result > 0) || (query.Desc && result < 0) {
+				start = i
+				break
+			}
+		}
+		matched = matched[start:]
+	}
+	if query.Limit > 0 && len(matched) > query.Limit {
+		matched = matched[:query.Limit]
+	}
+	return matched, total, nil
+}
+
+// 按排序列取值和 pod_id 比较两个位置
+func compareCursor(a, b *PodCursor) int {
+	if a.Value != b.Value {
+		return strings.Compare(a.Value, b.Value)
+	}
+	switch {
+	case a.ID < b.ID:
+		return -1
+	case a.ID > b.ID:
+		return 1
+	}
+	return 0
+}
+
+func matchPodQuery(pod *Pod, query *PodQuery) bool {
+	if query.Namespace != "" && pod.PodNameSpace != query.Namespace {
+		return false
+	}
+	if query.TeamID != 0 && pod.PodTeamID != query.TeamID {
+		return false
+	}
+	if len(query.TeamIDs) > 0 && !containsInt64(query.TeamIDs, pod.PodTeamID) {
+		return false
+	}
+	if query.Image != "" && !strings.Contains(pod.Image, query.Image) {
+		return false
+	}
+	if len(query.DeployType) > 0 && !containsString(query.DeployType, pod.PodDeployType) {
+		return false
+	}
+	if query.NamePrefix != "" && !strings.HasPrefix(pod.PodName, query.NamePrefix) {
+		return false
+	}
+	return true
+}
+
+// pod 名称在数据库中是唯一键
+func (m *MemoryPodRegistry) checkName(pod *Pod) error {
+	for id, other := range m.pods {
+		if id != pod.PodID && other.PodName == pod.PodName {
+			return fmt.Errorf("%w: pod name %s", ErrDuplicateKey, pod.PodName)
+		}
+	}
+	return nil
+}
+
+// 与数据库一样给没有 ID 的子表记录分配 ID，并填写 pod_id
+func (m *MemoryPodRegistry) assignChildIDs(pod *Pod) {
+	next := func(id *uint64) {
+		if *id == 0 {
+			m.lastChildID++
+			*id = m.lastChildID
+		}
+	}
+	for i := range pod.PodPorts {
+		id := uint64(pod.PodPorts[i].ID)
+		next(&id)
+		pod.PodPorts[i].ID, pod.PodPorts[i].PodID = uint(id), pod.PodID
+	}
+	for i := range pod.PodEnvs {
+		next(&pod.PodEnvs[i].ID)
+		pod.PodEnvs[i].PodID = pod.PodID
+	}
+	for i := range pod.PodProbes {
+		next(&pod.PodProbes[i].ID)
+		pod.PodProbes[i].PodID = pod.PodID
+	}
+	for i := range pod.PodVolumes {
+		next(&pod.PodVolumes[i].ID)
+		pod.PodVolumes[i].PodID = pod.PodID
+	}
+	for i := range pod.PodContainers {
+		next(&pod.PodContainers[i].ID)
+		pod.PodContainers[i].PodID = pod.PodID
+	}
+	//init 容器按 ID 顺序读取
+	sort.SliceStable(pod.PodContainers, func(i, j int) bool {
+		return pod.PodContainers[i].ID < pod.PodContainers[j].ID
+	})
+}
+
+// 深拷贝，调用方修改返回值不影响存储中的数据
+func clonePod(pod *Pod) *Pod {
+	data, err := json.Marshal(pod)
+	if err != nil {
+		panic(err)
+	}
+	result := &Pod{}
+	if err := json.Unmarshal(data, result); err != nil {
+		panic(err)
+	}
+	return result
+}
+
+func containsInt64(values []int64, value int64) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// MemoryRevisionRegistry 把版本快照保存在内存中
+type MemoryRevisionRegistry struct {
+	mu        sync.RWMutex
+	revisions map[uint64][]PodRevision
+	lastID    uint64
+}
+
+func NewMemoryRevisionRegistry() *MemoryRevisionRegistry {
+	return &MemoryRevisionRegistry{
+		revisions: map[uint64][]PodRevision{},
+	}
+}
+
+func (m *MemoryRevisionRegistry) CreateRevision(revision *PodRevision) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	revisions := m.revisions[revision.PodID]
+	revision.Revision = int64(len(revisions)) + 1
+	if len(revisions) > 0 {
+		revision.Revision = revisions[len(revisions)-1].Revision + 1
+	}
+	m.lastID++
+	revision.ID = m.lastID
+	revision.CreatedAt = time.Now()
+	m.revisions[revision.PodID] = append(revisions, *revision)
+	return nil
+}
+
+func (m *MemoryRevisionRegistry) GetRevisions(podID uint64) ([]PodRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	revisions := m.revisions[podID]
+	result := make([]PodRevision, 0, len(revisions))
+	for i := len(revisions) - 1; i >= 0; i-- {
+		result = append(result, revisions[i])
+	}
+	return result, nil
+}
+
+func (m *MemoryRevisionRegistry) GetRevision(podID uint64, revision int64) (*PodRevision, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	for _, podRevision := range m.revisions[podID] {
+		if podRevision.Revision == revision {
+			return &podRevision, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+// MemoryQuotaRegistry 把团队配额保存在内存中，用量从内存中的 pod 计算
+type MemoryQuotaRegistry struct {
+	mu     sync.RWMutex
+	quotas map[int64]TeamQuota
+	pods   *MemoryPodRegistry
+	lastID uint64
+}
+
+func NewMemoryQuotaRegistry(pods *MemoryPodRegistry) *MemoryQuotaRegistry {
+	return &MemoryQuotaRegistry{
+		quotas: map[int64]TeamQuota{},
+		pods:   pods,
+	}
+}
+
+func (m *MemoryQuotaRegistry) GetQuota(teamID int64) (*TeamQuota, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	quota, ok := m.quotas[teamID]
+	if !ok {
+		return nil, nil
+	}
+	quota.Namespaces = append([]string(nil), quota.Namespaces...)
+	return &quota, nil
+}
+
+func (m *MemoryQuotaRegistry) SetQuota(quota *TeamQuota) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if previous, ok := m.quotas[quota.TeamID]; ok {
+		quota.ID = previous.ID
+	} else {
+		m.lastID++
+		quota.ID = m.lastID
+	}
+	saved := *quota
+	saved.Namespaces = append([]string(nil), quota.Namespaces...)
+	m.quotas[quota.TeamID] = saved
+	return nil
+}
+
+func (m *MemoryQuotaRegistry) GetTeamPods(teamID int64) ([]Pod, error) {
+	pods, _, err := m.pods.Find(&PodQuery{TeamID: teamID})
+	return pods, err
+}
@@ -0,0 +1,14 @@
+package model_test
+
+import (
+	"testing"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/model/podtest"
+)
+
+func TestMemoryPodRegistry(t *testing.T) {
+	podtest.Run(t, func(t *testing.T) model.IPod {
+		return model.NewMemoryPodRegistry()
+	})
+}
@@ -1,30 +1,113 @@
 package model
 
 import (
-	"log"
+	"fmt"
+	"reflect"
 
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 type PodPort struct {
-	ID       uint `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
-	PodID    uint64
+	ID       uint   `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	PodID    uint64 `gorm:"index"`
 	Port     int32  `json:"port"`
 	Protocol string `json:"protocol"`
+	//service 端口映射
+	ServicePort int32 `json:"service_port"`
+	NodePort    int32 `json:"node_port"`
+	//所属容器，主容器为 pod 名称
+	ContainerName string `json:"container_name"`
 }
 
 type PodEnv struct {
 	ID       uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
-	PodID    uint64
+	PodID    uint64 `gorm:"index"`
 	EnvKey   string `json:"env_key"`
 	EnvValue string `json:"env_value"`
+	//值保存在 k8s secret 中，这里只存掩码
+	Secret bool `json:"secret"`
+	//所属容器，主容器为 pod 名称
+	ContainerName string `json:"container_name"`
+}
+
+type PodProbe struct {
+	ID        uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	PodID     uint64 `gorm:"index"`
+	ProbeType string `json:"probe_type"`
+	Handler   string `json:"handler"`
+	//http_get
+	Path   string `json:"path"`
+	Port   int32  `json:"port"`
+	Scheme string `json:"scheme"`
+	//exec
+	Command []string `gorm:"serializer:json" json:"command"`
+	//grpc
+	GrpcService         string `json:"grpc_service"`
+	InitialDelaySeconds int32  `json:"initial_delay_seconds"`
+	PeriodSeconds       int32  `json:"period_seconds"`
+	TimeoutSeconds      int32  `json:"timeout_seconds"`
+	SuccessThreshold    int32  `json:"success_threshold"`
+	FailureThreshold    int32  `json:"failure_threshold"`
+}
+
+type PodVolume struct {
+	ID         uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	PodID      uint64 `gorm:"index"`
+	Name       string `json:"name"`
+	VolumeType string `json:"volume_type"`
+	//pvc
+	ClaimName    string `json:"claim_name"`
+	StorageSize  string `json:"storage_size"`
+	StorageClass string `json:"storage_class"`
+	AccessMode   string `json:"access_mode"`
+	//config_map/secret 名称，host_path 路径
+	Source    string `json:"source"`
+	MountPath string `json:"mount_path"`
+	SubPath   string `json:"sub_path"`
+	ReadOnly  bool   `json:"read_only"`
+}
+
+// PodContainer 是主容器之外的 sidecar 容器或 init 容器，环境变量和端口按容器名称保存在 pod_env/pod_port 中
+type PodContainer struct {
+	ID         uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	PodID      uint64 `gorm:"index"`
+	Name       string `json:"name"`
+	Kind       string `gorm:"default:'container'" json:"kind"`
+	Image      string `gorm:"not null" json:"image"`
+	PullPolicy string `json:"pull_policy"`
+	//覆盖镜像的 entrypoint 和 cmd
+	Command    []string `gorm:"serializer:json" json:"command"`
+	Args       []string `gorm:"serializer:json" json:"args"`
+	WorkingDir string   `json:"working_dir"`
+	//k8s quantity 格式
+	MaxCpu          string              `json:"max_cpu"`
+	MinCpu          string              `json:"min_cpu"`
+	MaxMem          string              `json:"max_mem"`
+	MinMem          string              `json:"min_mem"`
+	VolumeMounts    []PodVolumeMount    `gorm:"serializer:json" json:"volume_mounts"`
+	SecurityContext *PodSecurityContext `gorm:"serializer:json" json:"security_context"`
+}
+
+type PodSecurityContext struct {
+	RunAsUser              *int64   `json:"run_as_user,omitempty"`
+	RunAsNonRoot           bool     `json:"run_as_non_root"`
+	ReadOnlyRootFilesystem bool     `json:"read_only_root_filesystem"`
+	DropCapabilities       []string `json:"drop_capabilities"`
+}
+
+type PodVolumeMount struct {
+	Name      string `json:"name"`
+	MountPath string `json:"mount_path"`
+	SubPath   string `json:"sub_path"`
+	ReadOnly  bool   `json:"read_only"`
 }
 
 type Pod struct {
 	PodID            uint64    `gorm:"primaryKey;not null" json:"pod_id"`
 	PodName          string    `gorm:"unique;not null" json:"pod_name"`
-	PodNameSpace     string    `json:"pod_namespace"`
-	PodTeamID        int64     `json:"pod_team_id"`
+	PodNameSpace     string    `gorm:"index:idx_pod_namespace" json:"pod_namespace"`
+	PodTeamID        int64     `gorm:"index:idx_pod_team_id" json:"pod_team_id"`
 	PodMaxCpuUsage   float64   `json:"pod_max_cpu_usage"`
 	PodMinCpuUsage   float64   `json:"pod_min_cpu_usage"`
 	PodMaxMemUsage   float64   `json:"pod_max_mem_usage"`
@@ -34,78 +117,328 @@ type Pod struct {
 	Image            string    `gorm:"not null" json:"image"`
 	PodPullPolicy    string    `gorm:"default:'if_not_present'" json:"pod_pull_policy"`
 	PodRestartPolicy string    `gorm:"default:'always'" json:"pod_restart_policy"`
-	PodDeployType    string    `json:"pod_deploy_type"`
+	PodDeployType    string    `gorm:"index:idx_pod_deploy_type" json:"pod_deploy_type"`
 	Replicas         int32     `json:"replicas"`
+	//statefulset 存储
+	PodStorageSize  string `json:"pod_storage_size"`
+	PodStorageClass string `json:"pod_storage_class"`
+	PodStoragePath  string `json:"pod_storage_path"`
+	//service 类型
+	PodServiceType string `gorm:"default:'cluster_ip'" json:"pod_service_type"`
+	//健康检查
+	PodProbes []PodProbe `gorm:"foreignKey:pod_id;references:pod_id" json:"pod_probes"`
+	//存储卷
+	PodVolumes []PodVolume `gorm:"foreignKey:pod_id;references:pod_id" json:"pod_volumes"`
+	//sidecar 和 init 容器
+	PodContainers []PodContainer `gorm:"foreignKey:pod_id;references:pod_id" json:"pod_containers"`
+	//主容器的启动命令
+	PodCommand    []string `gorm:"serializer:json" json:"pod_command"`
+	PodArgs       []string `gorm:"serializer:json" json:"pod_args"`
+	PodWorkingDir string   `json:"pod_working_dir"`
+	//主容器的安全配置
+	PodSecurityContext *PodSecurityContext `gorm:"serializer:json" json:"pod_security_context"`
+	//乐观锁版本号，每次更新加 1，更新时必须带上读取时的版本
+	Version int64 `gorm:"not null;default:1" json:"version"`
+}
+
+// VersionConflictError 表示 pod 在读取之后已被其他请求修改，Current 为数据库中的当前版本
+type VersionConflictError struct {
+	PodID   uint64
+	Version int64
+	Current int64
+}
+
+func (e *VersionConflictError) Error() string {
+	return fmt.Sprintf("pod id:%d 版本冲突,请求版本 %d,当前版本 %d", e.PodID, e.Version, e.Current)
 }
 
 type IPod interface {
-	//初始化表
-	InitTable() error
 	//根据ID查找数据
 	GetById(uint64) (*Pod, error)
 	//创建一个Pod
 	CreatePod(*Pod) (uint64, error)
 	//删除pod
 	DeletePod(uint64) error
-	//更新Pod
+	//更新Pod，版本号与数据库不一致时返回 *VersionConflictError，成功后版本号加 1
 	UpdatePod(*Pod) error
 	//查找所有
 	Get() ([]Pod, error)
+	//更新副本数，版本号加 1
+	UpdateReplicas(uint64, int32) error
+	//按条件分页查询
+	Find(*PodQuery) ([]Pod, int64, error)
 }
 
-func NewPodRegistry(db *gorm.DB) *PodRegistry {
+// encryptor 为 nil 时不加密
+func NewPodRegistry(db *gorm.DB, encryptor Encryptor) *PodRegistry {
+	if encryptor == nil {
+		encryptor = NoopEncryptor{}
+	}
 	return &PodRegistry{
-		db: db,
+		db:        db,
+		encryptor: encryptor,
 	}
 }
 
 type PodRegistry struct {
 	db *gorm.DB
-}
-
-func (p *PodRegistry) InitTable() error {
-	log.Println("自动迁移数据库")
-	return p.db.AutoMigrate(&Pod{}, &PodEnv{}, &PodPort{})
-
+	//加密敏感字段
+	encryptor Encryptor
 }
 
 func (p *PodRegistry) GetById(id uint64) (pod *Pod, err error) {
 	pod = &Pod{}
-	err = p.db.Preload("PodEnvs").Preload("PodPorts").First(pod, id).Error
+	err = p.db.Preload("PodEnvs").Preload("PodPorts").Preload("PodProbes").Preload("PodVolumes").Preload("PodContainers", orderByID).First(pod, id).Error
+	if err != nil {
+		return
+	}
+	err = p.decryptPod(pod)
 	return
 }
 
 func (p *PodRegistry) CreatePod(pod *Pod) (podId uint64, err error) {
-	podId = pod.PodID
-	err = p.db.Create(pod).Error
-	return
+	if pod.Version == 0 {
+		pod.Version = 1
+	}
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		//密文以 pod_id 作为附加数据，先写入 pod 取得数据库生成的 ID 再加密写入子表
+		if err := tx.Omit(clause.Associations).Create(pod).Error; err != nil {
+			return err
+		}
+		restore, err := p.encryptPod(pod)
+		if err != nil {
+			return err
+		}
+		defer restore()
+		for _, children := range []interface{}{&pod.PodEnvs, &pod.PodPorts, &pod.PodProbes, &pod.PodVolumes, &pod.PodContainers} {
+			if err := createChildren(tx, pod.PodID, children); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return
+	}
+	return pod.PodID, nil
+}
+
+// 写入新 pod 的子表记录，children 为子表结构体切片的指针
+func createChildren(tx *gorm.DB, podID uint64, children interface{}) error {
+	rows := reflect.ValueOf(children).Elem()
+	if rows.Len() == 0 {
+		return nil
+	}
+	for i := 0; i < rows.Len(); i++ {
+		rows.Index(i).FieldByName("PodID").SetUint(podID)
+	}
+	return tx.Create(children).Error
 }
 
 func (p *PodRegistry) DeletePod(id uint64) error {
-	tx := p.db.Begin()
-	tx.Where("pod_id = ?", id).Delete(&PodPort{})
-	tx.Where("pod_id = ?", id).Delete(&PodEnv{})
-	tx.Where("pod_id=?", id).Delete(&Pod{})
-	if err := tx.Commit().Error; err != nil {
-		tx.Callback()
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		for _, child := range []interface{}{&PodPort{}, &PodEnv{}, &PodProbe{}, &PodVolume{}, &PodContainer{}} {
+			if err := tx.Where("pod_id = ?", id).Delete(child).Error; err != nil {
+				return err
+			}
+		}
+		return tx.Where("pod_id=?", id).Delete(&Pod{}).Error
+	})
+}
+
+func (p *PodRegistry) UpdatePod(pod *Pod) error {
+	restore, err := p.encryptPod(pod)
+	if err != nil {
 		return err
 	}
-	return nil
+	defer restore()
+	version := pod.Version
+	err = p.db.Transaction(func(tx *gorm.DB) error {
+		//只有版本号与读取时一致才更新，否则说明已被其他请求修改
+		pod.Version = version + 1
+		result := tx.Model(pod).Where("version = ?", version).Select("*").Omit(clause.Associations).Updates(pod)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return versionConflict(tx, pod.PodID, version)
+		}
+		//子表按 ID 对比，删除新集合中没有的记录
+		for _, children := range []interface{}{&pod.PodEnvs, &pod.PodPorts, &pod.PodProbes, &pod.PodVolumes, &pod.PodContainers} {
+			if err := syncChildren(tx, pod.PodID, children); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		pod.Version = version
+	}
+	return err
 }
 
-func (p *PodRegistry) UpdatePod(pod *Pod) error {
-	tx := p.db.Begin()
-	tx.Association("pod_envs").DB.Save(pod.PodEnvs)
-	tx.Association("pod_ports").DB.Save(pod.PodPorts)
-	tx.Save(pod)
-	if err := tx.Commit().Error; err != nil {
-		tx.Callback()
+// 对比数据库中的子表记录和 children：新增没有 ID 的记录，更新有变化的记录，删除 children 中没有的记录
+// children 为子表结构体切片的指针，其他 pod 的记录 ID 视为新增，不会被改到这个 pod 下
+func syncChildren(tx *gorm.DB, podID uint64, children interface{}) error {
+	rows := reflect.ValueOf(children).Elem()
+	existing := reflect.New(rows.Type())
+	if err := tx.Where("pod_id = ?", podID).Find(existing.Interface()).Error; err != nil {
 		return err
 	}
-	return nil
+	current := map[uint64]reflect.Value{}
+	for i := 0; i < existing.Elem().Len(); i++ {
+		row := existing.Elem().Index(i)
+		current[row.FieldByName("ID").Uint()] = row
+	}
+	for i := 0; i < rows.Len(); i++ {
+		row := rows.Index(i)
+		row.FieldByName("PodID").SetUint(podID)
+		id := row.FieldByName("ID")
+		previous, ok := current[id.Uint()]
+		if !ok {
+			id.SetUint(0)
+			if err := tx.Create(row.Addr().Interface()).Error; err != nil {
+				return err
+			}
+			continue
+		}
+		delete(current, id.Uint())
+		if reflect.DeepEqual(previous.Interface(), row.Interface()) {
+			continue
+		}
+		if err := tx.Save(row.Addr().Interface()).Error; err != nil {
+			return err
+		}
+	}
+	if len(current) == 0 {
+		return nil
+	}
+	removed := make([]uint64, 0, len(current))
+	for id := range current {
+		removed = append(removed, id)
+	}
+	return tx.Where("id IN ?", removed).Delete(reflect.New(rows.Type().Elem()).Interface()).Error
+}
+
+// 条件更新没有命中时区分 pod 不存在和版本冲突
+func versionConflict(db *gorm.DB, podID uint64, version int64) error {
+	current := &Pod{}
+	if err := db.Select("version").First(current, podID).Error; err != nil {
+		return err
+	}
+	return &VersionConflictError{PodID: podID, Version: version, Current: current.Version}
 }
 
 func (p *PodRegistry) Get() (pods []Pod, err error) {
-	err = p.db.Preload("PodEnvs").Preload("PodPorts").Find(&pods).Error
-	return pods, err
+	err = p.db.Preload("PodEnvs").Preload("PodPorts").Preload("PodProbes").Preload("PodVolumes").Preload("PodContainers", orderByID).Find(&pods).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods {
+		if err := p.decryptPod(&pods[i]); err != nil {
+			return nil, err
+		}
+	}
+	return pods, nil
+}
+
+func (p *PodRegistry) UpdateReplicas(id uint64, replicas int32) error {
+	return p.db.Model(&Pod{}).Where("pod_id = ?", id).Updates(map[string]interface{}{
+		"replicas": replicas,
+		"version":  gorm.Expr("version + 1"),
+	}).Error
+}
+
+// Reencrypt 用当前主密钥重新加密所有敏感字段，返回更新的行数，轮换密钥后执行
+func (p *PodRegistry) Reencrypt() (count int, err error) {
+	var envs []PodEnv
+	err = p.db.FindInBatches(&envs, 100, func(tx *gorm.DB, batch int) error {
+		for i := range envs {
+			value, changed, err := reencrypt(p.encryptor, envs[i].EnvValue, envContext(&envs[i]))
+			if err != nil {
+				return fmt.Errorf("pod_env id:%d 重新加密失败: %v", envs[i].ID, err)
+			}
+			if !changed {
+				continue
+			}
+			if err := p.db.Model(&PodEnv{}).Where("id = ?", envs[i].ID).Update("env_value", value).Error; err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	}).Error
+	return
+}
+
+// init 容器按顺序执行，按写入顺序读取
+func orderByID(db *gorm.DB) *gorm.DB {
+	return db.Order("id")
+}
+
+// 需要加密保存的字段及其加密上下文
+type sensitiveField struct {
+	value   *string
+	context string
+}
+
+func sensitiveFields(pod *Pod) (fields []sensitiveField) {
+	for i := range pod.PodEnvs {
+		env := &pod.PodEnvs[i]
+		env.PodID = pod.PodID
+		fields = append(fields, sensitiveField{value: &env.EnvValue, context: envContext(env)})
+	}
+	return
+}
+
+// 环境变量的值绑定到所在的 pod、容器和变量名
+func envContext(env *PodEnv) string {
+	return fmt.Sprintf("pod_env:%d:%s:%s", env.PodID, env.ContainerName, env.EnvKey)
+}
+
+// 原地加密敏感字段，返回的函数把字段恢复为明文，调用方的对象在写库之后不受影响
+func (p *PodRegistry) encryptPod(pod *Pod) (func(), error) {
+	fields := sensitiveFields(pod)
+	plain := make([]string, len(fields))
+	restore := func() {
+		for i, field := range fields {
+			*field.value = plain[i]
+		}
+	}
+	for i, field := range fields {
+		plain[i] = *field.value
+	}
+	for _, field := range fields {
+		encrypted, err := p.encryptor.Encrypt(*field.value, field.context)
+		if err != nil {
+			restore()
+			return nil, err
+		}
+		*field.value = encrypted
+	}
+	return restore, nil
+}
+
+func (p *PodRegistry) decryptPod(pod *Pod) error {
+	for _, field := range sensitiveFields(pod) {
+		plain, err := p.encryptor.Decrypt(*field.value, field.context)
+		if err != nil {
+			return fmt.Errorf("pod %s 解密失败: %v", pod.PodName, err)
+		}
+		*field.value = plain
+	}
+	return nil
+}
+
+// 解密后用主密钥重新加密，不需要重新加密时 changed 为 false
+func reencrypt(encryptor Encryptor, value, context string) (result string, changed bool, err error) {
+	if !encryptor.NeedsReencrypt(value) {
+		return value, false, nil
+	}
+	plain, err := encryptor.Decrypt(value, context)
+	if err != nil {
+		return "", false, err
+	}
+	result, err = encryptor.Encrypt(plain, context)
+	return result, err == nil, err
 }
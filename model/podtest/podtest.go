@@ -0,0 +1,455 @@
+// Package podtest 是 model.IPod 的一致性测试，每个存储实现都必须通过
+//
+// 在存储实现的测试中调用 Run，每个用例都会通过 newRegistry 取得一个空的仓库：
+//
+//	podtest.Run(t, func(t *testing.T) model.IPod {
+//		return model.NewMemoryPodRegistry()
+//	})
+package podtest
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"testing"
+
+	"github.com/jary-287/gopass-pod/model"
+	"gorm.io/gorm"
+)
+
+// Run 依次执行所有用例，newRegistry 返回的仓库必须已经初始化表并且没有数据
+func Run(t *testing.T, newRegistry func(t *testing.T) model.IPod) {
+	cases := []struct {
+		name string
+		fn   func(t *testing.T, pods model.IPod)
+	}{
+		{"CreateAndGet", testCreateAndGet},
+		{"CreateAssignsID", testCreateAssignsID},
+		{"DuplicateName", testDuplicateName},
+		{"GetMissing", testGetMissing},
+		{"Update", testUpdate},
+		{"UpdateChildren", testUpdateChildren},
+		{"UpdateVersion", testUpdateVersion},
+		{"UpdateMissing", testUpdateMissing},
+		{"UpdateReplicas", testUpdateReplicas},
+		{"Delete", testDelete},
+		{"Get", testGet},
+		{"FindFilters", testFindFilters},
+		{"FindPagination", testFindPagination},
+		{"FindInvalidOrder", testFindInvalidOrder},
+	}
+	for _, c := range cases {
+		c := c
+		t.Run(c.name, func(t *testing.T) {
+			c.fn(t, newRegistry(t))
+		})
+	}
+}
+
+// 一个带子表的完整 pod
+func newPod(id uint64, name string) *model.Pod {
+	return &model.Pod{
+		PodID:         id,
+		PodName:       name,
+		PodNameSpace:  "default",
+		PodTeamID:     1,
+		Image:         "nginx:1.25",
+		PodDeployType: "deployment",
+		Replicas:      2,
+		PodEnvs: []model.PodEnv{
+			{EnvKey: "MODE", EnvValue: "prod", ContainerName: name},
+			{EnvKey: "TOKEN", EnvValue: "secret", ContainerName: "sidecar"},
+		},
+		PodPorts: []model.PodPort{
+			{Port: 80, Protocol: "TCP", ContainerName: name},
+		},
+		PodProbes: []model.PodProbe{
+			{ProbeType: "liveness", Handler: "http_get", Path: "/healthz", Port: 80},
+		},
+		PodVolumes: []model.PodVolume{
+			{Name: "data", VolumeType: "empty_dir", MountPath: "/data"},
+		},
+		PodContainers: []model.PodContainer{
+			{Name: "init-db", Kind: "init", Image: "busybox", Command: []string{"sh", "-c", "true"}},
+			{Name: "sidecar", Kind: "container", Image: "envoy"},
+		},
+		PodCommand: []string{"nginx"},
+		PodArgs:    []string{"-g", "daemon off;"},
+	}
+}
+
+func mustCreate(t *testing.T, pods model.IPod, pod *model.Pod) uint64 {
+	t.Helper()
+	id, err := pods.CreatePod(pod)
+	if err != nil {
+		t.Fatalf("CreatePod(%s) 失败: %v", pod.PodName, err)
+	}
+	return id
+}
+
+func mustGet(t *testing.T, pods model.IPod, id uint64) *model.Pod {
+	t.Helper()
+	pod, err := pods.GetById(id)
+	if err != nil {
+		t.Fatalf("GetById(%d) 失败: %v", id, err)
+	}
+	return pod
+}
+
+func testCreateAndGet(t *testing.T, pods model.IPod) {
+	id := mustCreate(t, pods, newPod(10, "web"))
+	if id != 10 {
+		t.Fatalf("CreatePod 返回的 ID = %d, 期望 10", id)
+	}
+	got := mustGet(t, pods, 10)
+	want := newPod(10, "web")
+	if got.PodName != want.PodName || got.Image != want.Image || got.Replicas != want.Replicas ||
+		got.PodNameSpace != want.PodNameSpace || got.PodTeamID != want.PodTeamID {
+		t.Errorf("GetById 返回 %+v, 期望 %+v", got, want)
+	}
+	if fmt.Sprint(got.PodCommand, got.PodArgs) != fmt.Sprint(want.PodCommand, want.PodArgs) {
+		t.Errorf("启动命令 = %v %v, 期望 %v %v", got.PodCommand, got.PodArgs, want.PodCommand, want.PodArgs)
+	}
+	if len(got.PodEnvs) != 2 || len(got.PodPorts) != 1 || len(got.PodProbes) != 1 || len(got.PodVolumes) != 1 {
+		t.Fatalf("子表数量不对: envs=%d ports=%d probes=%d volumes=%d",
+			len(got.PodEnvs), len(got.PodPorts), len(got.PodProbes), len(got.PodVolumes))
+	}
+	envs := map[string]model.PodEnv{}
+	for _, env := range got.PodEnvs {
+		if env.ID == 0 || env.PodID != 10 {
+			t.Errorf("环境变量 %s 的 ID=%d PodID=%d", env.EnvKey, env.ID, env.PodID)
+		}
+		envs[env.EnvKey] = env
+	}
+	if envs["MODE"].EnvValue != "prod" || envs["TOKEN"].EnvValue != "secret" || envs["TOKEN"].ContainerName != "sidecar" {
+		t.Errorf("环境变量 = %+v", got.PodEnvs)
+	}
+	//init 容器必须按写入顺序返回
+	if len(got.PodContainers) != 2 || got.PodContainers[0].Name != "init-db" || got.PodContainers[1].Name != "sidecar" {
+		t.Fatalf("容器 = %+v", got.PodContainers)
+	}
+	if fmt.Sprint(got.PodContainers[0].Command) != fmt.Sprint([]string{"sh", "-c", "true"}) {
+		t.Errorf("容器命令 = %v", got.PodContainers[0].Command)
+	}
+}
+
+func testCreateAssignsID(t *testing.T, pods model.IPod) {
+	first := mustCreate(t, pods, newPod(0, "a"))
+	second := mustCreate(t, pods, newPod(0, "b"))
+	if first == 0 || second == 0 || first == second {
+		t.Fatalf("自动分配的 ID = %d, %d", first, second)
+	}
+	if pod := mustGet(t, pods, second); pod.PodName != "b" {
+		t.Errorf("GetById(%d) 返回 %s, 期望 b", second, pod.PodName)
+	}
+}
+
+func testDuplicateName(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	if _, err := pods.CreatePod(newPod(2, "web")); err == nil {
+		t.Fatal("pod 名称重复时 CreatePod 应该返回错误")
+	}
+}
+
+func testGetMissing(t *testing.T, pods model.IPod) {
+	if _, err := pods.GetById(404); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("GetById 不存在的 pod 返回 %v, 期望 gorm.ErrRecordNotFound", err)
+	}
+}
+
+func testUpdate(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	pod := mustGet(t, pods, 1)
+	pod.Image = "nginx:1.26"
+	pod.Replicas = 5
+	for i := range pod.PodEnvs {
+		if pod.PodEnvs[i].EnvKey == "MODE" {
+			pod.PodEnvs[i].EnvValue = "staging"
+		}
+	}
+	pod.PodEnvs = append(pod.PodEnvs, model.PodEnv{EnvKey: "DEBUG", EnvValue: "1", ContainerName: "web"})
+	if err := pods.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod 失败: %v", err)
+	}
+	got := mustGet(t, pods, 1)
+	if got.Image != "nginx:1.26" || got.Replicas != 5 {
+		t.Errorf("更新后 image=%s replicas=%d", got.Image, got.Replicas)
+	}
+	values := map[string]string{}
+	for _, env := range got.PodEnvs {
+		values[env.EnvKey] = env.EnvValue
+	}
+	if len(got.PodEnvs) != 3 || values["MODE"] != "staging" || values["DEBUG"] != "1" {
+		t.Errorf("更新后的环境变量 = %+v", got.PodEnvs)
+	}
+}
+
+func testUpdateChildren(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	mustCreate(t, pods, newPod(2, "api"))
+	pod := mustGet(t, pods, 1)
+	var envs []model.PodEnv
+	for _, env := range pod.PodEnvs {
+		if env.EnvKey == "MODE" {
+			env.EnvValue = "staging"
+			envs = append(envs, env)
+		}
+	}
+	pod.PodEnvs = envs
+	pod.PodPorts = nil
+	pod.PodProbes[0].Path = "/ready"
+	probeID, volumeID := pod.PodProbes[0].ID, pod.PodVolumes[0].ID
+	pod.PodContainers = pod.PodContainers[:1]
+	if err := pods.UpdatePod(pod); err != nil {
+		t.Fatalf("UpdatePod 失败: %v", err)
+	}
+	got := mustGet(t, pods, 1)
+	//删除的子表记录不能残留
+	if len(got.PodEnvs) != 1 || got.PodEnvs[0].EnvKey != "MODE" || got.PodEnvs[0].EnvValue != "staging" {
+		t.Errorf("更新后的环境变量 = %+v", got.PodEnvs)
+	}
+	if len(got.PodPorts) != 0 {
+		t.Errorf("删除的端口仍然存在: %+v", got.PodPorts)
+	}
+	if len(got.PodContainers) != 1 || got.PodContainers[0].Name != "init-db" {
+		t.Errorf("更新后的容器 = %+v", got.PodContainers)
+	}
+	//修改和未修改的记录保留原来的 ID
+	if len(got.PodProbes) != 1 || got.PodProbes[0].ID != probeID || got.PodProbes[0].Path != "/ready" {
+		t.Errorf("更新后的健康检查 = %+v", got.PodProbes)
+	}
+	if len(got.PodVolumes) != 1 || got.PodVolumes[0].ID != volumeID {
+		t.Errorf("更新后的存储卷 = %+v", got.PodVolumes)
+	}
+	other := mustGet(t, pods, 2)
+	if len(other.PodEnvs) != 2 || len(other.PodPorts) != 1 || len(other.PodContainers) != 2 {
+		t.Errorf("更新影响了其他 pod 的子表: %+v", other)
+	}
+}
+
+func testUpdateVersion(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	first := mustGet(t, pods, 1)
+	second := mustGet(t, pods, 1)
+	if first.Version != 1 {
+		t.Fatalf("新建 pod 的版本 = %d, 期望 1", first.Version)
+	}
+	first.Image = "nginx:1.26"
+	if err := pods.UpdatePod(first); err != nil {
+		t.Fatalf("UpdatePod 失败: %v", err)
+	}
+	if first.Version != 2 {
+		t.Errorf("更新后调用方的版本 = %d, 期望 2", first.Version)
+	}
+	//基于旧版本的更新不能覆盖
+	second.Image = "nginx:1.24"
+	second.PodEnvs = nil
+	err := pods.UpdatePod(second)
+	var conflict *model.VersionConflictError
+	if !errors.As(err, &conflict) {
+		t.Fatalf("旧版本的 UpdatePod 返回 %v, 期望 *model.VersionConflictError", err)
+	}
+	if conflict.Current != 2 || conflict.Version != 1 {
+		t.Errorf("冲突的版本 = %+v", conflict)
+	}
+	got := mustGet(t, pods, 1)
+	if got.Image != "nginx:1.26" || got.Version != 2 || len(got.PodEnvs) != 2 {
+		t.Errorf("冲突的更新修改了数据: %+v", got)
+	}
+	//副本数也是修改
+	if err := pods.UpdateReplicas(1, 3); err != nil {
+		t.Fatalf("UpdateReplicas 失败: %v", err)
+	}
+	if got := mustGet(t, pods, 1); got.Version != 3 {
+		t.Errorf("UpdateReplicas 后版本 = %d, 期望 3", got.Version)
+	}
+}
+
+func testUpdateMissing(t *testing.T, pods model.IPod) {
+	pod := newPod(404, "missing")
+	pod.Version = 1
+	if err := pods.UpdatePod(pod); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Fatalf("UpdatePod 不存在的 pod 返回 %v, 期望 gorm.ErrRecordNotFound", err)
+	}
+	if _, err := pods.GetById(404); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("UpdatePod 写入了不存在的 pod: %v", err)
+	}
+}
+
+func testUpdateReplicas(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	if err := pods.UpdateReplicas(1, 7); err != nil {
+		t.Fatalf("UpdateReplicas 失败: %v", err)
+	}
+	if got := mustGet(t, pods, 1); got.Replicas != 7 {
+		t.Errorf("replicas = %d, 期望 7", got.Replicas)
+	}
+	//其他字段不受影响
+	if got := mustGet(t, pods, 1); got.Image != "nginx:1.25" || len(got.PodEnvs) != 2 {
+		t.Errorf("UpdateReplicas 修改了其他字段: %+v", got)
+	}
+}
+
+func testDelete(t *testing.T, pods model.IPod) {
+	mustCreate(t, pods, newPod(1, "web"))
+	mustCreate(t, pods, newPod(2, "api"))
+	if err := pods.DeletePod(1); err != nil {
+		t.Fatalf("DeletePod 失败: %v", err)
+	}
+	if _, err := pods.GetById(1); !errors.Is(err, gorm.ErrRecordNotFound) {
+		t.Errorf("删除后 GetById 返回 %v", err)
+	}
+	//同名 pod 可以重新创建，子表不会带上旧数据
+	mustCreate(t, pods, &model.Pod{PodID: 3, PodName: "web", Image: "nginx"})
+	if got := mustGet(t, pods, 3); len(got.PodEnvs) != 0 || len(got.PodContainers) != 0 {
+		t.Errorf("重新创建的 pod 带有旧的子表: %+v", got)
+	}
+	if got := mustGet(t, pods, 2); len(got.PodEnvs) != 2 {
+		t.Errorf("删除影响了其他 pod 的子表: %+v", got.PodEnvs)
+	}
+}
+
+func testGet(t *testing.T, pods model.IPod) {
+	for i, name := range []string{"a", "b", "c"} {
+		mustCreate(t, pods, newPod(uint64(i+1), name))
+	}
+	all, err := pods.Get()
+	if err != nil {
+		t.Fatalf("Get 失败: %v", err)
+	}
+	if len(all) != 3 {
+		t.Fatalf("Get 返回 %d 个 pod, 期望 3", len(all))
+	}
+	for _, pod := range all {
+		if len(pod.PodEnvs) != 2 || len(pod.PodContainers) != 2 {
+			t.Errorf("Get 没有加载 pod %s 的子表", pod.PodName)
+		}
+	}
+}
+
+// 用于查询的一组 pod
+func seedQueryPods(t *testing.T, pods model.IPod) {
+	t.Helper()
+	seeds := []struct {
+		name, namespace, image, deployType string
+		team                               int64
+	}{
+		{"api-1", "prod", "registry/api:1", "deployment", 1},
+		{"api-2", "prod", "registry/api:2", "", 1},
+		{"db-1", "prod", "mysql:8", "statefulset", 2},
+		{"web_1", "dev", "nginx:1.25", "deployment", 2},
+		{"web-2", "dev", "nginx:1.26", "daemonset", 3},
+		{"worker", "dev", "registry/api:2", "deployment", 3},
+	}
+	for i, seed := range seeds {
+		mustCreate(t, pods, &model.Pod{
+			PodID:         uint64(i + 1),
+			PodName:       seed.name,
+			PodNameSpace:  seed.namespace,
+			PodTeamID:     seed.team,
+			Image:         seed.image,
+			PodDeployType: seed.deployType,
+			PodEnvs:       []model.PodEnv{{EnvKey: "NAME", EnvValue: seed.name, ContainerName: seed.name}},
+		})
+	}
+}
+
+func names(pods []model.Pod) []string {
+	result := make([]string, 0, len(pods))
+	for _, pod := range pods {
+		result = append(result, pod.PodName)
+	}
+	return result
+}
+
+func sortedNames(pods []model.Pod) string {
+	result := names(pods)
+	sort.Strings(result)
+	return fmt.Sprint(result)
+}
+
+func testFindFilters(t *testing.T, pods model.IPod) {
+	seedQueryPods(t, pods)
+	cases := []struct {
+		name  string
+		query model.PodQuery
+		want  []string
+	}{
+		{"all", model.PodQuery{}, []string{"api-1", "api-2", "db-1", "web-2", "web_1", "worker"}},
+		{"namespace", model.PodQuery{Namespace: "prod"}, []string{"api-1", "api-2", "db-1"}},
+		{"team", model.PodQuery{TeamID: 2}, []string{"db-1", "web_1"}},
+		{"teams", model.PodQuery{TeamIDs: []int64{1, 3}}, []string{"api-1", "api-2", "web-2", "worker"}},
+		{"image", model.PodQuery{Image: "api:2"}, []string{"api-2", "worker"}},
+		{"deploy type", model.PodQuery{DeployType: []string{"deployment", ""}}, []string{"api-1", "api-2", "web_1", "worker"}},
+		//前缀中的 _ 不能当作通配符
+		{"name prefix", model.PodQuery{NamePrefix: "web_"}, []string{"web_1"}},
+		{"combined", model.PodQuery{Namespace: "dev", TeamIDs: []int64{3}, NamePrefix: "w"}, []string{"web-2", "worker"}},
+	}
+	for _, c := range cases {
+		query := c.query
+		result, total, err := pods.Find(&query)
+		if err != nil {
+			t.Errorf("%s: Find 失败: %v", c.name, err)
+			continue
+		}
+		want := make([]string, len(c.want))
+		copy(want, c.want)
+		sort.Strings(want)
+		if sortedNames(result) != fmt.Sprint(want) {
+			t.Errorf("%s: Find 返回 %v, 期望 %v", c.name, names(result), c.want)
+		}
+		if total != int64(len(c.want)) {
+			t.Errorf("%s: total = %d, 期望 %d", c.name, total, len(c.want))
+		}
+	}
+	//查询结果带子表
+	result, _, _ := pods.Find(&model.PodQuery{NamePrefix: "db"})
+	if len(result) != 1 || len(result[0].PodEnvs) != 1 || result[0].PodEnvs[0].EnvValue != "db-1" {
+		t.Errorf("Find 没有加载子表: %+v", result)
+	}
+}
+
+func testFindPagination(t *testing.T, pods model.IPod) {
+	seedQueryPods(t, pods)
+	cases := []struct {
+		orderBy string
+		desc    bool
+		want    []string
+	}{
+		{"pod_id", false, []string{"api-1", "api-2", "db-1", "web_1", "web-2", "worker"}},
+		{"pod_id", true, []string{"worker", "web-2", "web_1", "db-1", "api-2", "api-1"}},
+		{"pod_name", false, []string{"api-1", "api-2", "db-1", "web-2", "web_1", "worker"}},
+		{"pod_name", true, []string{"worker", "web_1", "web-2", "db-1", "api-2", "api-1"}},
+		//排序列取值相同时按 pod_id 排序
+		{"pod_namespace", false, []string{"web_1", "web-2", "worker", "api-1", "api-2", "db-1"}},
+		{"pod_namespace", true, []string{"db-1", "api-2", "api-1", "worker", "web-2", "web_1"}},
+		{"image", false, []string{"db-1", "web_1", "web-2", "api-1", "api-2", "worker"}},
+	}
+	for _, c := range cases {
+		var got []string
+		var after *model.PodCursor
+		for page := 0; page < 10; page++ {
+			result, total, err := pods.Find(&model.PodQuery{OrderBy: c.orderBy, Desc: c.desc, Limit: 4, After: after})
+			if err != nil {
+				t.Fatalf("%s desc=%v: Find 失败: %v", c.orderBy, c.desc, err)
+			}
+			if total != 6 {
+				t.Errorf("%s desc=%v: total = %d, 期望 6", c.orderBy, c.desc, total)
+			}
+			got = append(got, names(result)...)
+			if len(result) < 4 {
+				break
+			}
+			after = result[len(result)-1].Cursor(c.orderBy)
+		}
+		if fmt.Sprint(got) != fmt.Sprint(c.want) {
+			t.Errorf("%s desc=%v: 分页结果 %v, 期望 %v", c.orderBy, c.desc, got, c.want)
+		}
+	}
+}
+
+func testFindInvalidOrder(t *testing.T, pods model.IPod) {
+	if _, _, err := pods.Find(&model.PodQuery{OrderBy: "replicas; drop table pod"}); err == nil {
+		t.Fatal("不支持的排序字段应该返回错误")
+	}
+}
@@ -0,0 +1,122 @@
+package model
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// 允许排序的字段和对应的列，都是可以按字符串比较的列，pod_id 单独处理
+var podOrderColumns = map[string]string{
+	"pod_id":        "pod_id",
+	"pod_name":      "pod_name",
+	"pod_namespace": "pod_name_space",
+	"image":         "image",
+}
+
+// PodQuery 是分页查询 pod 的条件，空值表示不过滤
+type PodQuery struct {
+	Namespace  string
+	TeamID     int64
+	TeamIDs    []int64
+	Image      string
+	DeployType []string
+	NamePrefix string
+	OrderBy    string
+	Desc       bool
+	//为 0 时不限制
+	Limit int
+	//上一页最后一行，为 nil 时从第一页开始
+	After *PodCursor
+}
+
+// PodCursor 记录上一页最后一行的排序列取值和 pod_id
+type PodCursor struct {
+	Value string `json:"v"`
+	ID    uint64 `json:"id"`
+}
+
+// Cursor 返回 pod 在指定排序列下的游标
+func (p *Pod) Cursor(orderBy string) *PodCursor {
+	cursor := &PodCursor{ID: p.PodID}
+	switch orderBy {
+	case "pod_name":
+		cursor.Value = p.PodName
+	case "pod_namespace":
+		cursor.Value = p.PodNameSpace
+	case "image":
+		cursor.Value = p.Image
+	}
+	return cursor
+}
+
+// Find 按条件分页查询，返回当前页和符合条件的总数，子表只为当前页加载
+func (p *PodRegistry) Find(query *PodQuery) (pods []Pod, total int64, err error) {
+	orderBy := query.OrderBy
+	if orderBy == "" {
+		orderBy = "pod_id"
+	}
+	column, ok := podOrderColumns[orderBy]
+	if !ok {
+		return nil, 0, fmt.Errorf("不支持的排序字段: %s", query.OrderBy)
+	}
+	db := p.db.Model(&Pod{})
+	if query.Namespace != "" {
+		db = db.Where("pod_name_space = ?", query.Namespace)
+	}
+	if query.TeamID != 0 {
+		db = db.Where("pod_team_id = ?", query.TeamID)
+	}
+	if len(query.TeamIDs) > 0 {
+		db = db.Where("pod_team_id IN ?", query.TeamIDs)
+	}
+	if query.Image != "" {
+		db = db.Where("image LIKE ? ESCAPE '!'", "%"+escapeLike(query.Image)+"%")
+	}
+	if len(query.DeployType) > 0 {
+		db = db.Where("pod_deploy_type IN ?", query.DeployType)
+	}
+	if query.NamePrefix != "" {
+		db = db.Where("pod_name LIKE ? ESCAPE '!'", escapeLike(query.NamePrefix)+"%")
+	}
+	if err = db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	//按排序列加 pod_id 做 keyset 分页，排序列取值相同时用 pod_id 区分
+	direction, compare := "asc", ">"
+	if query.Desc {
+		direction, compare = "desc", "<"
+	}
+	if cursor := query.After; cursor != nil {
+		if orderBy == "pod_id" {
+			db = db.Where("pod_id "+compare+" ?", cursor.ID)
+		} else {
+			db = db.Where("(("+column+" "+compare+" ?) OR ("+column+" = ? AND pod_id "+compare+" ?))",
+				cursor.Value, cursor.Value, cursor.ID)
+		}
+	}
+	if orderBy != "pod_id" {
+		db = db.Order(column + " " + direction)
+	}
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	err = db.Order("pod_id "+direction).
+		Preload("PodEnvs").Preload("PodPorts").Preload("PodProbes").Preload("PodVolumes").
+		Preload("PodContainers", orderByID).Find(&pods).Error
+	if err != nil {
+		return nil, 0, err
+	}
+	for i := range pods {
+		if err := p.decryptPod(&pods[i]); err != nil {
+			return nil, 0, err
+		}
+	}
+	return pods, total, nil
+}
+
+// 转义 LIKE 中的通配符，mysql 和 sqlite 对反斜杠的处理不同，用 ! 作为转义字符
+func escapeLike(value string) string {
+	return strings.NewReplacer("!", "!!", "%", "!%", "_", "!_").Replace(value)
+}
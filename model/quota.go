@@ -0,0 +1,62 @@
+package model
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TeamQuota 限制一个团队所有 pod 的资源总量，0 表示不限制
+type TeamQuota struct {
+	ID     uint64 `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	TeamID int64  `gorm:"uniqueIndex;not null" json:"team_id"`
+	//cpu 单位为核，内存单位为 Mi，按 limits 乘以副本数计算
+	MaxCpu      float64 `json:"max_cpu"`
+	MaxMem      float64 `json:"max_mem"`
+	MaxReplicas int64   `json:"max_replicas"`
+	MaxPods     int64   `json:"max_pods"`
+	//同步 ResourceQuota 的命名空间
+	Namespaces []string `gorm:"serializer:json" json:"namespaces"`
+}
+
+type IQuota interface {
+	//查找团队配额，没有配置时返回 nil
+	GetQuota(int64) (*TeamQuota, error)
+	//按团队写入配额
+	SetQuota(*TeamQuota) error
+	//查找团队的所有 pod，只加载计算用量需要的容器
+	GetTeamPods(int64) ([]Pod, error)
+}
+
+func NewQuotaRegistry(db *gorm.DB) *QuotaRegistry {
+	return &QuotaRegistry{
+		db: db,
+	}
+}
+
+type QuotaRegistry struct {
+	db *gorm.DB
+}
+
+func (q *QuotaRegistry) GetQuota(teamID int64) (*TeamQuota, error) {
+	//没有配置配额是常见情况，不用 First 避免记录 record not found 日志
+	var quotas []TeamQuota
+	if err := q.db.Where("team_id = ?", teamID).Limit(1).Find(&quotas).Error; err != nil {
+		return nil, err
+	}
+	if len(quotas) == 0 {
+		return nil, nil
+	}
+	return &quotas[0], nil
+}
+
+func (q *QuotaRegistry) SetQuota(quota *TeamQuota) error {
+	return q.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "team_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"max_cpu", "max_mem", "max_replicas", "max_pods", "namespaces"}),
+	}).Create(quota).Error
+}
+
+func (q *QuotaRegistry) GetTeamPods(teamID int64) (pods []Pod, err error) {
+	err = q.db.Where("pod_team_id = ?", teamID).Preload("PodContainers").Find(&pods).Error
+	return
+}
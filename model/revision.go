@@ -0,0 +1,114 @@
+package model
+
+import (
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// PodRevision 保存每次创建/更新时完整的 PodInfo 快照
+type PodRevision struct {
+	ID        uint64    `gorm:"primaryKey;not null;AUTO_INCREMENT" json:"id,omitempty"`
+	PodID     uint64    `gorm:"uniqueIndex:idx_pod_revision;not null" json:"pod_id"`
+	Revision  int64     `gorm:"uniqueIndex:idx_pod_revision;not null" json:"revision"`
+	Spec      string    `gorm:"type:mediumtext" json:"spec"`
+	Action    string    `json:"action"`
+	ChangedBy string    `json:"changed_by"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type IPodRevision interface {
+	//写入一个新版本，版本号自动递增
+	CreateRevision(*PodRevision) error
+	//查找 pod 的所有版本
+	GetRevisions(uint64) ([]PodRevision, error)
+	//查找 pod 的指定版本
+	GetRevision(uint64, int64) (*PodRevision, error)
+}
+
+// 快照中包含环境变量，和 pod 表使用同一个加密器
+func NewPodRevisionRegistry(db *gorm.DB, encryptor Encryptor) *PodRevisionRegistry {
+	if encryptor == nil {
+		encryptor = NoopEncryptor{}
+	}
+	return &PodRevisionRegistry{
+		db:        db,
+		encryptor: encryptor,
+	}
+}
+
+type PodRevisionRegistry struct {
+	db        *gorm.DB
+	encryptor Encryptor
+}
+
+func (p *PodRevisionRegistry) CreateRevision(revision *PodRevision) error {
+	return p.db.Transaction(func(tx *gorm.DB) error {
+		var last int64
+		if err := tx.Model(&PodRevision{}).Where("pod_id = ?", revision.PodID).
+			Select("COALESCE(MAX(revision), 0)").Scan(&last).Error; err != nil {
+			return err
+		}
+		revision.Revision = last + 1
+		plain := revision.Spec
+		spec, err := p.encryptor.Encrypt(plain, revisionContext(revision.PodID, revision.Revision))
+		if err != nil {
+			return err
+		}
+		revision.Spec = spec
+		defer func() { revision.Spec = plain }()
+		return tx.Create(revision).Error
+	})
+}
+
+func (p *PodRevisionRegistry) GetRevisions(podID uint64) (revisions []PodRevision, err error) {
+	err = p.db.Where("pod_id = ?", podID).Order("revision desc").Find(&revisions).Error
+	if err != nil {
+		return nil, err
+	}
+	for i := range revisions {
+		context := revisionContext(revisions[i].PodID, revisions[i].Revision)
+		if revisions[i].Spec, err = p.encryptor.Decrypt(revisions[i].Spec, context); err != nil {
+			return nil, err
+		}
+	}
+	return
+}
+
+func (p *PodRevisionRegistry) GetRevision(podID uint64, revision int64) (podRevision *PodRevision, err error) {
+	podRevision = &PodRevision{}
+	err = p.db.Where("pod_id = ? AND revision = ?", podID, revision).First(podRevision).Error
+	if err != nil {
+		return
+	}
+	podRevision.Spec, err = p.encryptor.Decrypt(podRevision.Spec, revisionContext(podID, revision))
+	return
+}
+
+// 快照绑定到所属的 pod 和版本
+func revisionContext(podID uint64, revision int64) string {
+	return fmt.Sprintf("pod_revision:%d:%d", podID, revision)
+}
+
+// Reencrypt 用当前主密钥重新加密所有快照，返回更新的行数
+func (p *PodRevisionRegistry) Reencrypt() (count int, err error) {
+	var revisions []PodRevision
+	err = p.db.FindInBatches(&revisions, 100, func(tx *gorm.DB, batch int) error {
+		for i := range revisions {
+			spec, changed, err := reencrypt(p.encryptor, revisions[i].Spec, revisionContext(revisions[i].PodID, revisions[i].Revision))
+			if err != nil {
+				return fmt.Errorf("pod_revision id:%d 重新加密失败: %v", revisions[i].ID, err)
+			}
+			if !changed {
+				continue
+			}
+			if err := p.db.Model(&PodRevision{}).Where("id = ?", revisions[i].ID).Update("spec", spec).Error; err != nil {
+				return err
+			}
+			count++
+		}
+		return nil
+	}).Error
+	return
+}
@@ -0,0 +1,146 @@
+package model_test
+
+import (
+	"bytes"
+	"encoding/base64"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/model/podtest"
+)
+
+// 在临时目录中打开 sqlite 存储并迁移到最新版本
+func openSqlite(t *testing.T, encryptor model.Encryptor) *model.Storage {
+	t.Helper()
+	return openSqliteFile(t, filepath.Join(t.TempDir(), "pod.db"), encryptor)
+}
+
+func openSqliteFile(t *testing.T, path string, encryptor model.Encryptor) *model.Storage {
+	t.Helper()
+	storage, err := model.OpenStorage(model.StorageSqlite, path, encryptor)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { storage.Close() })
+	if _, err := storage.Migrator().Up(0); err != nil {
+		t.Fatal(err)
+	}
+	return storage
+}
+
+func TestSqlitePodRegistry(t *testing.T) {
+	podtest.Run(t, func(t *testing.T) model.IPod {
+		return openSqlite(t, nil).Pods
+	})
+}
+
+// 加密保存时读写结果与明文存储一致
+func TestEncryptedSqlitePodRegistry(t *testing.T) {
+	encryptor, err := model.NewAESGCMEncryptor(&model.KeyFile{
+		Primary: "k1",
+		Keys:    map[string]string{"k1": base64.StdEncoding.EncodeToString(make([]byte, 32))},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	podtest.Run(t, func(t *testing.T) model.IPod {
+		return openSqlite(t, encryptor).Pods
+	})
+	//数据库中只有密文
+	storage := openSqlite(t, encryptor)
+	if _, err := storage.Pods.CreatePod(&model.Pod{
+		PodName: "web", Image: "nginx",
+		PodEnvs: []model.PodEnv{{EnvKey: "MODE", EnvValue: "prod", ContainerName: "web"}},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	var stored string
+	if err := storage.DB().Table("pod_env").Select("env_value").Scan(&stored).Error; err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(stored, "enc:v1:") {
+		t.Errorf("env_value = %q, want 密文", stored)
+	}
+}
+
+// 密钥 id 为 k1、k2 ...，密钥的每个字节都是 id 中的数字字符
+func testKeyEncryptor(t *testing.T, primary string, keys ...string) *model.AESGCMEncryptor {
+	t.Helper()
+	keyFile := &model.KeyFile{Primary: primary, Keys: map[string]string{}}
+	for _, id := range keys {
+		keyFile.Keys[id] = base64.StdEncoding.EncodeToString(bytes.Repeat([]byte(id[1:]), 32))
+	}
+	encryptor, err := model.NewAESGCMEncryptor(keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return encryptor
+}
+
+// 轮换主密钥并重新加密后，只保留新密钥也能读取环境变量和版本快照
+func TestSqliteKeyRotation(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pod.db")
+	storage := openSqliteFile(t, path, testKeyEncryptor(t, "k1", "k1"))
+	podID, err := storage.Pods.CreatePod(&model.Pod{
+		PodName: "web", Image: "nginx",
+		PodEnvs: []model.PodEnv{{EnvKey: "TOKEN", EnvValue: "secret-value", ContainerName: "web"}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := storage.Revisions.CreateRevision(&model.PodRevision{PodID: podID, Revision: 1, Spec: `{"image":"nginx"}`}); err != nil {
+		t.Fatal(err)
+	}
+	storage.Close()
+
+	//新密钥成为主密钥，旧密钥保留用于解密
+	storage = openSqliteFile(t, path, testKeyEncryptor(t, "k2", "k1", "k2"))
+	for name, registry := range map[string]interface{}{"pod_env": storage.Pods, "pod_revision": storage.Revisions} {
+		count, err := registry.(model.Reencrypter).Reencrypt()
+		if err != nil || count != 1 {
+			t.Fatalf("%s 重新加密 %d 行, %v, want 1 行", name, count, err)
+		}
+	}
+	storage.Close()
+
+	storage = openSqliteFile(t, path, testKeyEncryptor(t, "k2", "k2"))
+	for table, column := range map[string]string{"pod_env": "env_value", "pod_revision": "spec"} {
+		var stored string
+		if err := storage.DB().Table(table).Select(column).Scan(&stored).Error; err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(stored, "enc:v1:k2:") {
+			t.Errorf("%s.%s = %q, want k2 加密的密文", table, column, stored)
+		}
+	}
+	podModel, err := storage.Pods.GetById(podID)
+	if err != nil {
+		t.Fatalf("只有新密钥时读取 pod 失败: %v", err)
+	}
+	if len(podModel.PodEnvs) != 1 || podModel.PodEnvs[0].EnvValue != "secret-value" {
+		t.Errorf("envs = %+v, want TOKEN=secret-value", podModel.PodEnvs)
+	}
+	revision, err := storage.Revisions.GetRevision(podID, 1)
+	if err != nil {
+		t.Fatalf("只有新密钥时读取快照失败: %v", err)
+	}
+	if revision.Spec != `{"image":"nginx"}` {
+		t.Errorf("spec = %s, want 原快照", revision.Spec)
+	}
+
+	//移动到其他变量或其他 pod 的密文不能解密
+	if err := storage.DB().Exec("UPDATE pod_env SET env_key = 'PASSWORD'").Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Pods.GetById(podID); err == nil {
+		t.Error("改名后的环境变量解密成功, want 失败")
+	}
+	if err := storage.DB().Exec("UPDATE pod_revision SET pod_id = ?", podID+1).Error; err != nil {
+		t.Fatal(err)
+	}
+	if _, err := storage.Revisions.GetRevision(podID+1, 1); err == nil {
+		t.Error("移动到其他 pod 的快照解密成功, want 失败")
+	}
+}
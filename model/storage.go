@@ -0,0 +1,122 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"log"
+
+	"github.com/jary-287/gopass-pod/migrate"
+	"gorm.io/driver/mysql"
+	"gorm.io/driver/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+// 存储类型
+const (
+	StorageMysql  = "mysql"
+	StorageSqlite = "sqlite"
+	StorageMemory = "memory"
+)
+
+// ErrDuplicateKey 表示写入的记录与已有记录的唯一键冲突，内存存储使用
+var ErrDuplicateKey = errors.New("记录已存在")
+
+// Storage 是按配置打开的存储，各仓库共用同一个数据库连接，内存存储没有数据库连接
+type Storage struct {
+	Driver    string
+	Pods      IPod
+	Revisions IPodRevision
+	Quotas    IQuota
+	db        *gorm.DB
+}
+
+// Reencrypter 由加密保存敏感字段的仓库实现，轮换密钥后重新加密
+type Reencrypter interface {
+	Reencrypt() (int, error)
+}
+
+// OpenStorage 按存储类型打开存储，mysql 的 dsn 为连接串，sqlite 的 dsn 为文件路径，内存存储忽略 dsn
+func OpenStorage(driver, dsn string, encryptor Encryptor) (*Storage, error) {
+	var dialector gorm.Dialector
+	switch driver {
+	case StorageMysql:
+		dialector = mysql.Open(dsn)
+	case StorageSqlite:
+		dialector = sqlite.Open(dsn)
+	case StorageMemory:
+		pods := NewMemoryPodRegistry()
+		return &Storage{
+			Driver:    driver,
+			Pods:      pods,
+			Revisions: NewMemoryRevisionRegistry(),
+			Quotas:    NewMemoryQuotaRegistry(pods),
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的存储类型: %s,可选值 mysql/sqlite/memory", driver)
+	}
+	db, err := openDB(dialector)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("%s connection success", driver)
+	if driver == StorageSqlite {
+		//sqlite 只支持一个写连接，多个连接同时写会返回 database is locked
+		sqlDB, _ := db.DB()
+		sqlDB.SetMaxOpenConns(1)
+	}
+	return &Storage{
+		Driver:    driver,
+		Pods:      NewPodRegistry(db, encryptor),
+		Revisions: NewPodRevisionRegistry(db, encryptor),
+		Quotas:    NewQuotaRegistry(db),
+		db:        db,
+	}, nil
+}
+
+func openDB(dialector gorm.Dialector) (*gorm.DB, error) {
+	db, err := gorm.Open(dialector, &gorm.Config{
+		SkipDefaultTransaction:                   true, //禁止默认事务
+		DisableForeignKeyConstraintWhenMigrating: true, //关闭创建外键约束
+		NamingStrategy: schema.NamingStrategy{
+			SingularTable: true, //禁止复表
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
+	}
+	// SetMaxIdleConns sets the maximum number of connections in the idle connection pool.
+	sqlDB.SetMaxIdleConns(10)
+	// SetMaxOpenConns sets the maximum number of open connections to the database.
+	sqlDB.SetMaxOpenConns(100)
+	return db, nil
+}
+
+// Migrator 返回数据库表结构的迁移器，内存存储没有表，返回 nil
+func (s *Storage) Migrator() *migrate.Migrator {
+	if s.db == nil {
+		return nil
+	}
+	return migrate.New(s.db, migrate.Migrations)
+}
+
+// DB 返回数据库连接，内存存储返回 nil
+func (s *Storage) DB() *gorm.DB {
+	return s.db
+}
+
+// Close 关闭数据库连接
+func (s *Storage) Close() error {
+	if s.db == nil {
+		return nil
+	}
+	sqlDB, err := s.db.DB()
+	if err != nil {
+		return err
+	}
+	return sqlDB.Close()
+}
@@ -41,6 +41,15 @@ type PodService interface {
 	FindPodById(ctx context.Context, in *PodId, opts ...client.CallOption) (*PodInfo, error)
 	UpdatePod(ctx context.Context, in *PodInfo, opts ...client.CallOption) (*Response, error)
 	FindAllPod(ctx context.Context, in *FindAll, opts ...client.CallOption) (*AllPod, error)
+	GetPodStatus(ctx context.Context, in *PodId, opts ...client.CallOption) (*PodStatus, error)
+	StreamPodLogs(ctx context.Context, in *PodLogRequest, opts ...client.CallOption) (Pod_StreamPodLogsService, error)
+	ScalePod(ctx context.Context, in *ScaleRequest, opts ...client.CallOption) (*Response, error)
+	RestartPod(ctx context.Context, in *PodId, opts ...client.CallOption) (*Response, error)
+	ListPodRevisions(ctx context.Context, in *PodId, opts ...client.CallOption) (*PodRevisions, error)
+	RollbackPod(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*Response, error)
+	SetTeamQuota(ctx context.Context, in *TeamQuota, opts ...client.CallOption) (*Response, error)
+	GetTeamQuota(ctx context.Context, in *TeamId, opts ...client.CallOption) (*TeamQuota, error)
+	PatchPod(ctx context.Context, in *PatchPodRequest, opts ...client.CallOption) (*Response, error)
 }
 
 type podService struct {
@@ -105,6 +114,135 @@ func (c *podService) FindAllPod(ctx context.Context, in *FindAll, opts ...client
 	return out, nil
 }
 
+func (c *podService) GetPodStatus(ctx context.Context, in *PodId, opts ...client.CallOption) (*PodStatus, error) {
+	req := c.c.NewRequest(c.name, "Pod.GetPodStatus", in)
+	out := new(PodStatus)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) StreamPodLogs(ctx context.Context, in *PodLogRequest, opts ...client.CallOption) (Pod_StreamPodLogsService, error) {
+	req := c.c.NewRequest(c.name, "Pod.StreamPodLogs", &PodLogRequest{})
+	stream, err := c.c.Stream(ctx, req, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if err := stream.Send(in); err != nil {
+		return nil, err
+	}
+	return &podServiceStreamPodLogs{stream}, nil
+}
+
+type Pod_StreamPodLogsService interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Recv() (*PodLogLine, error)
+}
+
+type podServiceStreamPodLogs struct {
+	stream client.Stream
+}
+
+func (x *podServiceStreamPodLogs) Close() error {
+	return x.stream.Close()
+}
+
+func (x *podServiceStreamPodLogs) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *podServiceStreamPodLogs) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *podServiceStreamPodLogs) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *podServiceStreamPodLogs) Recv() (*PodLogLine, error) {
+	m := new(PodLogLine)
+	err := x.stream.Recv(m)
+	if err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *podService) ScalePod(ctx context.Context, in *ScaleRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Pod.ScalePod", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) RestartPod(ctx context.Context, in *PodId, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Pod.RestartPod", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) ListPodRevisions(ctx context.Context, in *PodId, opts ...client.CallOption) (*PodRevisions, error) {
+	req := c.c.NewRequest(c.name, "Pod.ListPodRevisions", in)
+	out := new(PodRevisions)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) RollbackPod(ctx context.Context, in *RollbackRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Pod.RollbackPod", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) SetTeamQuota(ctx context.Context, in *TeamQuota, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Pod.SetTeamQuota", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) GetTeamQuota(ctx context.Context, in *TeamId, opts ...client.CallOption) (*TeamQuota, error) {
+	req := c.c.NewRequest(c.name, "Pod.GetTeamQuota", in)
+	out := new(TeamQuota)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *podService) PatchPod(ctx context.Context, in *PatchPodRequest, opts ...client.CallOption) (*Response, error) {
+	req := c.c.NewRequest(c.name, "Pod.PatchPod", in)
+	out := new(Response)
+	err := c.c.Call(ctx, req, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // Server API for Pod service
 
 type PodHandler interface {
@@ -113,6 +251,15 @@ type PodHandler interface {
 	FindPodById(context.Context, *PodId, *PodInfo) error
 	UpdatePod(context.Context, *PodInfo, *Response) error
 	FindAllPod(context.Context, *FindAll, *AllPod) error
+	GetPodStatus(context.Context, *PodId, *PodStatus) error
+	StreamPodLogs(context.Context, *PodLogRequest, Pod_StreamPodLogsStream) error
+	ScalePod(context.Context, *ScaleRequest, *Response) error
+	RestartPod(context.Context, *PodId, *Response) error
+	ListPodRevisions(context.Context, *PodId, *PodRevisions) error
+	RollbackPod(context.Context, *RollbackRequest, *Response) error
+	SetTeamQuota(context.Context, *TeamQuota, *Response) error
+	GetTeamQuota(context.Context, *TeamId, *TeamQuota) error
+	PatchPod(context.Context, *PatchPodRequest, *Response) error
 }
 
 func RegisterPodHandler(s server.Server, hdlr PodHandler, opts ...server.HandlerOption) error {
@@ -122,6 +269,15 @@ func RegisterPodHandler(s server.Server, hdlr PodHandler, opts ...server.Handler
 		FindPodById(ctx context.Context, in *PodId, out *PodInfo) error
 		UpdatePod(ctx context.Context, in *PodInfo, out *Response) error
 		FindAllPod(ctx context.Context, in *FindAll, out *AllPod) error
+		GetPodStatus(ctx context.Context, in *PodId, out *PodStatus) error
+		StreamPodLogs(ctx context.Context, stream server.Stream) error
+		ScalePod(ctx context.Context, in *ScaleRequest, out *Response) error
+		RestartPod(ctx context.Context, in *PodId, out *Response) error
+		ListPodRevisions(ctx context.Context, in *PodId, out *PodRevisions) error
+		RollbackPod(ctx context.Context, in *RollbackRequest, out *Response) error
+		SetTeamQuota(ctx context.Context, in *TeamQuota, out *Response) error
+		GetTeamQuota(ctx context.Context, in *TeamId, out *TeamQuota) error
+		PatchPod(ctx context.Context, in *PatchPodRequest, out *Response) error
 	}
 	type Pod struct {
 		pod
@@ -153,3 +309,75 @@ func (h *podHandler) UpdatePod(ctx context.Context, in *PodInfo, out *Response)
 func (h *podHandler) FindAllPod(ctx context.Context, in *FindAll, out *AllPod) error {
 	return h.PodHandler.FindAllPod(ctx, in, out)
 }
+
+func (h *podHandler) GetPodStatus(ctx context.Context, in *PodId, out *PodStatus) error {
+	return h.PodHandler.GetPodStatus(ctx, in, out)
+}
+
+func (h *podHandler) StreamPodLogs(ctx context.Context, stream server.Stream) error {
+	m := new(PodLogRequest)
+	if err := stream.Recv(m); err != nil {
+		return err
+	}
+	return h.PodHandler.StreamPodLogs(ctx, m, &podStreamPodLogsStream{stream})
+}
+
+type Pod_StreamPodLogsStream interface {
+	Context() context.Context
+	SendMsg(interface{}) error
+	RecvMsg(interface{}) error
+	Close() error
+	Send(*PodLogLine) error
+}
+
+type podStreamPodLogsStream struct {
+	stream server.Stream
+}
+
+func (x *podStreamPodLogsStream) Close() error {
+	return x.stream.Close()
+}
+
+func (x *podStreamPodLogsStream) Context() context.Context {
+	return x.stream.Context()
+}
+
+func (x *podStreamPodLogsStream) SendMsg(m interface{}) error {
+	return x.stream.Send(m)
+}
+
+func (x *podStreamPodLogsStream) RecvMsg(m interface{}) error {
+	return x.stream.Recv(m)
+}
+
+func (x *podStreamPodLogsStream) Send(m *PodLogLine) error {
+	return x.stream.Send(m)
+}
+
+func (h *podHandler) ScalePod(ctx context.Context, in *ScaleRequest, out *Response) error {
+	return h.PodHandler.ScalePod(ctx, in, out)
+}
+
+func (h *podHandler) RestartPod(ctx context.Context, in *PodId, out *Response) error {
+	return h.PodHandler.RestartPod(ctx, in, out)
+}
+
+func (h *podHandler) ListPodRevisions(ctx context.Context, in *PodId, out *PodRevisions) error {
+	return h.PodHandler.ListPodRevisions(ctx, in, out)
+}
+
+func (h *podHandler) RollbackPod(ctx context.Context, in *RollbackRequest, out *Response) error {
+	return h.PodHandler.RollbackPod(ctx, in, out)
+}
+
+func (h *podHandler) SetTeamQuota(ctx context.Context, in *TeamQuota, out *Response) error {
+	return h.PodHandler.SetTeamQuota(ctx, in, out)
+}
+
+func (h *podHandler) GetTeamQuota(ctx context.Context, in *TeamId, out *TeamQuota) error {
+	return h.PodHandler.GetTeamQuota(ctx, in, out)
+}
+
+func (h *podHandler) PatchPod(ctx context.Context, in *PatchPodRequest, out *Response) error {
+	return h.PodHandler.PatchPod(ctx, in, out)
+}
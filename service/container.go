@@ -0,0 +1,158 @@
+package service
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// 容器类型
+const (
+	ContainerKindContainer = "container"
+	ContainerKindInit      = "init"
+)
+
+// GetContainers 生成主容器之外的 sidecar 容器和 init 容器，init 容器保持请求中的顺序
+func (ps *PodService) GetContainers(info *pod.PodInfo) (containers, initContainers []v12.Container) {
+	for _, c := range info.PodContainers {
+		container := v12.Container{
+			Name:            c.Name,
+			Image:           c.Image,
+			ImagePullPolicy: GetPullPolicy(c.PullPolicy),
+			Command:         c.Command,
+			Args:            c.Args,
+			WorkingDir:      c.WorkingDir,
+			Ports:           getContainerPorts(c.Ports),
+			Env:             ps.GetEnvs(info, c.Name, c.Envs),
+			Resources:       GetContainerResource(c),
+			SecurityContext: GetSecurityContext(c.SecurityContext),
+		}
+		for _, mount := range c.VolumeMounts {
+			container.VolumeMounts = append(container.VolumeMounts, v12.VolumeMount{
+				Name:      mount.Name,
+				MountPath: mount.MountPath,
+				SubPath:   mount.SubPath,
+				ReadOnly:  mount.ReadOnly,
+			})
+		}
+		if strings.ToLower(c.Kind) == ContainerKindInit {
+			initContainers = append(initContainers, container)
+		} else {
+			containers = append(containers, container)
+		}
+	}
+	return
+}
+
+// GetContainerResource 把容器 quantity 格式的资源配置转换为 requests/limits，格式已在校验时检查
+func GetContainerResource(c *pod.PodContainer) (source v12.ResourceRequirements) {
+	source.Limits = v12.ResourceList{}
+	source.Requests = v12.ResourceList{}
+	setQuantity(source.Limits, v12.ResourceCPU, c.MaxCpu)
+	setQuantity(source.Limits, v12.ResourceMemory, c.MaxMem)
+	setQuantity(source.Requests, v12.ResourceCPU, c.MinCpu)
+	setQuantity(source.Requests, v12.ResourceMemory, c.MinMem)
+	return
+}
+
+func setQuantity(list v12.ResourceList, name v12.ResourceName, value string) {
+	if quantity, err := resource.ParseQuantity(value); err == nil && !quantity.IsZero() {
+		list[name] = quantity
+	}
+}
+
+// 校验 sidecar 和 init 容器的配置
+func validateContainers(info *pod.PodInfo) error {
+	names := map[string]bool{info.PodName: true}
+	volumes := map[string]bool{}
+	for _, volume := range info.PodVolumes {
+		volumes[volume.Name] = true
+	}
+	if info.PodStorageSize != "" {
+		volumes[storageVolumeName] = true
+	}
+	for _, c := range info.PodContainers {
+		if errs := validation.IsDNS1123Label(c.Name); len(errs) > 0 {
+			return fmt.Errorf("容器名称不合法 %s: %s", c.Name, strings.Join(errs, ","))
+		}
+		if names[c.Name] {
+			return fmt.Errorf("容器名称重复或与 pod 名称相同: %s", c.Name)
+		}
+		names[c.Name] = true
+		c.Kind = strings.ToLower(c.Kind)
+		switch c.Kind {
+		case "":
+			c.Kind = ContainerKindContainer
+		case ContainerKindContainer, ContainerKindInit:
+		default:
+			return fmt.Errorf("容器 %s 的类型不支持: %s,可选值 container/init", c.Name, c.Kind)
+		}
+		if c.Image == "" {
+			return fmt.Errorf("容器 %s 必须填写镜像", c.Name)
+		}
+		pullPolicy, err := normalizePolicy(c.PullPolicy, PullPolicyIfNotPresent, pullPolicies)
+		if err != nil {
+			return fmt.Errorf("容器 %s 的拉取策略%v", c.Name, err)
+		}
+		c.PullPolicy = pullPolicy
+		if err := validateWorkingDir(c.WorkingDir); err != nil {
+			return fmt.Errorf("容器 %s: %v", c.Name, err)
+		}
+		if err := validateSecurityContext(c.SecurityContext); err != nil {
+			return fmt.Errorf("容器 %s: %v", c.Name, err)
+		}
+		if err := validateContainerResource(c); err != nil {
+			return err
+		}
+		if err := validateEnvs(c.Envs); err != nil {
+			return fmt.Errorf("容器 %s: %v", c.Name, err)
+		}
+		for _, port := range c.Ports {
+			if port.Port < 1 || port.Port > 65535 {
+				return fmt.Errorf("容器 %s 的端口不合法: %d", c.Name, port.Port)
+			}
+		}
+		mountPaths := map[string]bool{}
+		for _, mount := range c.VolumeMounts {
+			if !volumes[mount.Name] {
+				return fmt.Errorf("容器 %s 挂载的存储卷不存在: %s", c.Name, mount.Name)
+			}
+			if !path.IsAbs(mount.MountPath) || mountPaths[mount.MountPath] {
+				return fmt.Errorf("容器 %s 的挂载路径必须是绝对路径且不能重复: %s", c.Name, mount.MountPath)
+			}
+			mountPaths[mount.MountPath] = true
+		}
+	}
+	return nil
+}
+
+func validateContainerResource(c *pod.PodContainer) error {
+	maxCpu, err := parseCpu(c.Name+" max_cpu", c.MaxCpu, 0)
+	if err != nil {
+		return err
+	}
+	minCpu, err := parseCpu(c.Name+" min_cpu", c.MinCpu, 0)
+	if err != nil {
+		return err
+	}
+	maxMem, err := parseMem(c.Name+" max_mem", c.MaxMem, 0)
+	if err != nil {
+		return err
+	}
+	minMem, err := parseMem(c.Name+" min_mem", c.MinMem, 0)
+	if err != nil {
+		return err
+	}
+	if maxCpu > 0 && minCpu > maxCpu {
+		return fmt.Errorf("容器 %s 的 cpu 最小值 %s 不能大于最大值 %s", c.Name, c.MinCpu, c.MaxCpu)
+	}
+	if maxMem > 0 && minMem > maxMem {
+		return fmt.Errorf("容器 %s 的内存最小值 %s 不能大于最大值 %s", c.Name, c.MinMem, c.MaxMem)
+	}
+	return nil
+}
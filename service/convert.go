@@ -0,0 +1,91 @@
+package service
+
+import (
+	"encoding/json"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+)
+
+// ModelToPodInfo 把数据库中的 pod 转换为 proto 结构，按容器名称把环境变量和端口分回各个容器
+func ModelToPodInfo(podModel *model.Pod) (*pod.PodInfo, error) {
+	primary := *podModel
+	primary.PodEnvs = nil
+	primary.PodPorts = nil
+	containerEnvs := map[string][]model.PodEnv{}
+	containerPorts := map[string][]model.PodPort{}
+	for _, env := range podModel.PodEnvs {
+		if isPrimaryContainer(podModel.PodName, env.ContainerName) {
+			primary.PodEnvs = append(primary.PodEnvs, env)
+			continue
+		}
+		containerEnvs[env.ContainerName] = append(containerEnvs[env.ContainerName], env)
+	}
+	for _, port := range podModel.PodPorts {
+		if isPrimaryContainer(podModel.PodName, port.ContainerName) {
+			primary.PodPorts = append(primary.PodPorts, port)
+			continue
+		}
+		containerPorts[port.ContainerName] = append(containerPorts[port.ContainerName], port)
+	}
+	info := &pod.PodInfo{}
+	if err := convert(&primary, info); err != nil {
+		return nil, err
+	}
+	for _, container := range info.PodContainers {
+		if err := convert(containerEnvs[container.Name], &container.Envs); err != nil {
+			return nil, err
+		}
+		if err := convert(containerPorts[container.Name], &container.Ports); err != nil {
+			return nil, err
+		}
+	}
+	return info, nil
+}
+
+// PodInfoToModel 把 proto 结构转换为数据库中的 pod，各容器的环境变量和端口带上容器名称平铺保存
+func PodInfoToModel(info *pod.PodInfo) (*model.Pod, error) {
+	podModel := &model.Pod{}
+	if err := convert(info, podModel); err != nil {
+		return nil, err
+	}
+	for i := range podModel.PodEnvs {
+		podModel.PodEnvs[i].ContainerName = info.PodName
+	}
+	for i := range podModel.PodPorts {
+		podModel.PodPorts[i].ContainerName = info.PodName
+	}
+	for _, container := range info.PodContainers {
+		var envs []model.PodEnv
+		if err := convert(container.Envs, &envs); err != nil {
+			return nil, err
+		}
+		for i := range envs {
+			envs[i].ContainerName = container.Name
+		}
+		podModel.PodEnvs = append(podModel.PodEnvs, envs...)
+		var ports []model.PodPort
+		if err := convert(container.Ports, &ports); err != nil {
+			return nil, err
+		}
+		for i := range ports {
+			ports[i].ContainerName = container.Name
+		}
+		podModel.PodPorts = append(podModel.PodPorts, ports...)
+	}
+	return podModel, nil
+}
+
+// 迁移之前的数据没有容器名称，同样属于主容器
+func isPrimaryContainer(podName, containerName string) bool {
+	return containerName == "" || containerName == podName
+}
+
+// 通过 json 在 proto 和数据库结构之间转换
+func convert(source interface{}, target interface{}) error {
+	data, err := json.Marshal(source)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, target)
+}
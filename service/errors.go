@@ -0,0 +1,234 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	microerrors "github.com/asim/go-micro/v3/errors"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jary-287/gopass-pod/model"
+	"gorm.io/gorm"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 返回给调用方的错误码，客户端按错误码判断错误类型，不要匹配错误信息
+const (
+	CodeNotFound        = "NOT_FOUND"
+	CodeConflict        = "CONFLICT"
+	CodeInvalidArgument = "INVALID_ARGUMENT"
+	CodeUnauthenticated = "UNAUTHENTICATED"
+	CodeForbidden       = "FORBIDDEN"
+	CodeK8sError        = "K8S_ERROR"
+	CodeDBError         = "DB_ERROR"
+	CodeInternal        = "INTERNAL"
+)
+
+// 错误码对应的 http 状态码，写入 go-micro 错误的 Code
+var errorStatus = map[string]int32{
+	CodeNotFound:        http.StatusNotFound,
+	CodeConflict:        http.StatusConflict,
+	CodeInvalidArgument: http.StatusBadRequest,
+	CodeUnauthenticated: http.StatusUnauthorized,
+	CodeForbidden:       http.StatusForbidden,
+	CodeK8sError:        http.StatusBadGateway,
+	CodeDBError:         http.StatusServiceUnavailable,
+	CodeInternal:        http.StatusInternalServerError,
+}
+
+// PodError 是带错误码的错误，Detail 为机器可读的补充信息，如校验失败的字段、k8s 的 reason
+type PodError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+	Detail  string `json:"detail,omitempty"`
+	Err     error  `json:"-"`
+}
+
+func (e *PodError) Error() string {
+	return e.Message
+}
+
+func (e *PodError) Unwrap() error {
+	return e.Err
+}
+
+func newPodError(code string, format string, a ...interface{}) *PodError {
+	return &PodError{Code: code, Message: fmt.Sprintf(format, a...)}
+}
+
+func NewNotFoundError(format string, a ...interface{}) error {
+	return newPodError(CodeNotFound, format, a...)
+}
+
+func NewConflictError(format string, a ...interface{}) error {
+	return newPodError(CodeConflict, format, a...)
+}
+
+func NewInvalidArgumentError(format string, a ...interface{}) error {
+	return newPodError(CodeInvalidArgument, format, a...)
+}
+
+func NewUnauthenticatedError(format string, a ...interface{}) error {
+	return newPodError(CodeUnauthenticated, format, a...)
+}
+
+func NewForbiddenError(format string, a ...interface{}) error {
+	return newPodError(CodeForbidden, format, a...)
+}
+
+// NewK8sError 包装调用 k8s 失败的错误，k8s 返回的 StatusError 在 Classify 中按 reason 转换
+func NewK8sError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var podErr *PodError
+	if errors.As(err, &podErr) {
+		return err
+	}
+	var status k8serrors.APIStatus
+	if errors.As(err, &status) {
+		return classifyK8sStatus(err, status.Status())
+	}
+	return &PodError{Code: CodeK8sError, Message: "调用 k8s 失败: " + err.Error(), Err: err}
+}
+
+// NewDBError 包装数据库错误，记录不存在和唯一键冲突转换为对应的错误码
+func NewDBError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var podErr *PodError
+	if errors.As(err, &podErr) {
+		return err
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &PodError{Code: CodeNotFound, Message: "记录不存在", Err: err}
+	}
+	var conflict *model.VersionConflictError
+	if errors.As(err, &conflict) {
+		return NewVersionConflictError(conflict.PodID, conflict.Version, conflict.Current)
+	}
+	if errors.Is(err, model.ErrDuplicateKey) {
+		//错误信息已经以 记录已存在 开头
+		return &PodError{Code: CodeConflict, Message: err.Error(), Err: err}
+	}
+	if isDuplicateKey(err) {
+		return &PodError{Code: CodeConflict, Message: "记录已存在: " + err.Error(), Err: err}
+	}
+	return &PodError{Code: CodeDBError, Message: "数据库错误: " + err.Error(), Err: err}
+}
+
+// NewVersionConflictError 表示更新基于的版本已经过期，Detail 为当前版本，调用方重新查询后再更新
+func NewVersionConflictError(podID uint64, version, current int64) error {
+	return &PodError{
+		Code:    CodeConflict,
+		Message: fmt.Sprintf("pod 已被其他请求修改,pod id:%d,请求版本:%d,当前版本:%d,请重新查询后再更新", podID, version, current),
+		Detail:  fmt.Sprintf("version:%d", current),
+	}
+}
+
+// 查询 k8s 中的工作负载失败时区分不存在和其他错误
+func workloadGetError(err error, podName string) error {
+	if k8serrors.IsNotFound(err) {
+		return NewNotFoundError("pod 不存在，请先创建,pod name:%s", podName)
+	}
+	return NewK8sError(err)
+}
+
+func isDuplicateKey(err error) bool {
+	if errors.Is(err, model.ErrDuplicateKey) {
+		return true
+	}
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		return mysqlErr.Number == 1062
+	}
+	return strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
+
+// Classify 把任意错误转换为带错误码的 PodError
+func Classify(err error) *PodError {
+	var podErr *PodError
+	if errors.As(err, &podErr) {
+		//外层有补充信息时保留完整的错误信息
+		if podErr.Message != err.Error() {
+			return &PodError{Code: podErr.Code, Message: err.Error(), Detail: podErr.Detail, Err: err}
+		}
+		return podErr
+	}
+	var validationErr *ValidationError
+	if errors.As(err, &validationErr) {
+		return &PodError{Code: CodeInvalidArgument, Message: err.Error(), Detail: validationErr.JSON(), Err: err}
+	}
+	var quotaErr *QuotaError
+	if errors.As(err, &quotaErr) {
+		return &PodError{Code: CodeForbidden, Message: err.Error(), Detail: "quota:" + quotaErr.Resource, Err: err}
+	}
+	var status k8serrors.APIStatus
+	if errors.As(err, &status) {
+		return classifyK8sStatus(err, status.Status())
+	}
+	var microErr *microerrors.Error
+	if errors.As(err, &microErr) {
+		return classifyMicroError(microErr)
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return &PodError{Code: CodeNotFound, Message: err.Error(), Err: err}
+	}
+	var conflict *model.VersionConflictError
+	if errors.As(err, &conflict) {
+		return NewVersionConflictError(conflict.PodID, conflict.Version, conflict.Current).(*PodError)
+	}
+	return &PodError{Code: CodeInternal, Message: err.Error(), Err: err}
+}
+
+// 按 k8s 的 reason 转换，Detail 中保留原始的 reason
+func classifyK8sStatus(err error, status metav1.Status) *PodError {
+	code := CodeK8sError
+	switch status.Reason {
+	case metav1.StatusReasonNotFound:
+		code = CodeNotFound
+	case metav1.StatusReasonAlreadyExists, metav1.StatusReasonConflict:
+		code = CodeConflict
+	case metav1.StatusReasonInvalid, metav1.StatusReasonBadRequest:
+		code = CodeInvalidArgument
+	case metav1.StatusReasonForbidden, metav1.StatusReasonUnauthorized:
+		//服务自己的 k8s 凭据没有权限，调用方无法处理，按 k8s 错误返回
+		code = CodeK8sError
+	}
+	return &PodError{Code: code, Message: err.Error(), Detail: "k8s:" + string(status.Reason), Err: err}
+}
+
+func classifyMicroError(err *microerrors.Error) *PodError {
+	code := CodeInternal
+	for name, status := range errorStatus {
+		if status == err.Code {
+			code = name
+		}
+	}
+	//已经是本服务生成的错误
+	podErr := &PodError{}
+	if json.Unmarshal([]byte(err.Detail), podErr) == nil && podErr.Code != "" {
+		return podErr
+	}
+	return &PodError{Code: code, Message: err.Detail, Err: err}
+}
+
+// ToMicroError 转换为 go-micro 错误，Code 为 http 状态码，Detail 为 PodError 的 json
+func ToMicroError(err error) error {
+	if err == nil {
+		return nil
+	}
+	podErr := Classify(err)
+	detail, _ := json.Marshal(podErr)
+	status := errorStatus[podErr.Code]
+	return &microerrors.Error{
+		Id:     "service.pod",
+		Code:   status,
+		Detail: string(detail),
+		Status: http.StatusText(int(status)),
+	}
+}
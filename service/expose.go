@@ -0,0 +1,142 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// service 类型
+const (
+	ServiceTypeClusterIP    = "cluster_ip"
+	ServiceTypeNodePort     = "node_port"
+	ServiceTypeLoadBalancer = "load_balancer"
+)
+
+// GetServiceType 把存储的 service 类型转换为 k8s 的取值，未填写时默认为 ClusterIP
+func GetServiceType(serviceType string) (v12.ServiceType, error) {
+	switch strings.ToLower(strings.TrimSpace(serviceType)) {
+	case "", ServiceTypeClusterIP, "clusterip":
+		return v12.ServiceTypeClusterIP, nil
+	case ServiceTypeNodePort, "nodeport":
+		return v12.ServiceTypeNodePort, nil
+	case ServiceTypeLoadBalancer, "loadbalancer":
+		return v12.ServiceTypeLoadBalancer, nil
+	default:
+		return "", fmt.Errorf("不支持的 service 类型: %s,可选值 cluster_ip/node_port/load_balancer", serviceType)
+	}
+}
+
+// GetService 根据端口配置生成与工作负载 app 标签匹配的 service，没有端口时返回 nil
+func (ps *PodService) GetService(info *pod.PodInfo) (*v12.Service, error) {
+	if len(info.PodPorts) == 0 {
+		return nil, nil
+	}
+	serviceType, err := GetServiceType(info.PodServiceType)
+	if err != nil {
+		return nil, err
+	}
+	service := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      info.PodName,
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app":    info.PodName,
+				"author": "ljw",
+			},
+		},
+		Spec: v12.ServiceSpec{
+			Type: serviceType,
+			Selector: map[string]string{
+				"app": info.PodName,
+			},
+		},
+	}
+	for _, port := range info.PodPorts {
+		servicePort := v12.ServicePort{
+			Name:       getServicePortName(port),
+			Protocol:   GetProtocol(port.Protocol),
+			Port:       port.ServicePort,
+			TargetPort: intstr.FromInt(int(port.Port)),
+		}
+		if servicePort.Port == 0 {
+			servicePort.Port = port.Port
+		}
+		if serviceType != v12.ServiceTypeClusterIP {
+			servicePort.NodePort = port.NodePort
+		}
+		service.Spec.Ports = append(service.Spec.Ports, servicePort)
+	}
+	return service, nil
+}
+
+// ApplyServiceToK8s 创建或更新 pod 的 service，端口被全部删除时同时删除 service
+func (ps *PodService) ApplyServiceToK8s(info *pod.PodInfo) error {
+	service, err := ps.GetService(info)
+	if err != nil {
+		return err
+	}
+	if service == nil {
+		return ps.DeleteServiceFromK8s(info)
+	}
+	services := ps.K8sClient.CoreV1().Services(info.PodNamespace)
+	current, err := services.Get(context.TODO(), info.PodName, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = services.Create(context.TODO(), service, metav1.CreateOptions{})
+		return err
+	}
+	if err != nil {
+		return err
+	}
+	//保留集群分配的 clusterIP 和未指定的 nodePort
+	for i := range service.Spec.Ports {
+		if service.Spec.Ports[i].NodePort != 0 || service.Spec.Type == v12.ServiceTypeClusterIP {
+			continue
+		}
+		for _, currentPort := range current.Spec.Ports {
+			if currentPort.Name == service.Spec.Ports[i].Name {
+				service.Spec.Ports[i].NodePort = currentPort.NodePort
+			}
+		}
+	}
+	current.Labels = service.Labels
+	current.Spec.Type = service.Spec.Type
+	current.Spec.Selector = service.Spec.Selector
+	current.Spec.Ports = service.Spec.Ports
+	_, err = services.Update(context.TODO(), current, metav1.UpdateOptions{})
+	return err
+}
+
+// CreateServiceToK8s 创建 pod 时创建 service，同名 service 已存在时返回冲突，不接管其他 service
+// 没有端口时不创建，created 表示本次是否新建了 service
+func (ps *PodService) CreateServiceToK8s(info *pod.PodInfo) (created bool, err error) {
+	service, err := ps.GetService(info)
+	if err != nil || service == nil {
+		return false, err
+	}
+	_, err = ps.K8sClient.CoreV1().Services(info.PodNamespace).Create(context.TODO(), service, metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		return false, NewConflictError("service 已经存在 name: %s", info.PodName)
+	}
+	return err == nil, err
+}
+
+// DeleteServiceFromK8s 删除 pod 的 service，不存在时忽略
+func (ps *PodService) DeleteServiceFromK8s(info *pod.PodInfo) error {
+	err := ps.K8sClient.CoreV1().Services(info.PodNamespace).Delete(context.TODO(), info.PodName, metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+// service 端口名称，多个端口时 k8s 要求必须填写
+func getServicePortName(port *pod.PodPort) string {
+	return fmt.Sprintf("%s-%d", strings.ToLower(string(GetProtocol(port.Protocol))), port.Port)
+}
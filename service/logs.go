@@ -0,0 +1,91 @@
+package service
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// StreamPodLogs 按 app 标签找到工作负载的所有 Pod，逐行推送日志，follow 时持续推送直到 ctx 结束
+func (ps *PodService) StreamPodLogs(ctx context.Context, podModel *model.Pod, req *pod.PodLogRequest, send func(*pod.PodLogLine) error) error {
+	pods, err := ps.K8sClient.CoreV1().Pods(podModel.PodNameSpace).List(ctx, metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"app": podModel.PodName}).String(),
+	})
+	if err != nil {
+		return err
+	}
+	if len(pods.Items) == 0 {
+		return NewNotFoundError("pod 没有运行中的实例,pod name:%s", podModel.PodName)
+	}
+	container := req.Container
+	if container == "" {
+		container = podModel.PodName
+	}
+	options := &v12.PodLogOptions{
+		Container: container,
+		Follow:    req.Follow,
+	}
+	if req.TailLines > 0 {
+		options.TailLines = &req.TailLines
+	}
+	if req.SinceTime > 0 {
+		since := metav1.NewTime(time.Unix(req.SinceTime, 0))
+		options.SinceTime = &since
+	}
+
+	//任意一个实例出错时结束所有日志流
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		sendLock sync.Mutex
+		errOnce  sync.Once
+		firstErr error
+	)
+	fail := func(err error) {
+		errOnce.Do(func() {
+			firstErr = err
+			cancel()
+		})
+	}
+	for _, instance := range pods.Items {
+		wg.Add(1)
+		go func(podName string) {
+			defer wg.Done()
+			stream, err := ps.K8sClient.CoreV1().Pods(podModel.PodNameSpace).GetLogs(podName, options).Stream(ctx)
+			if err != nil {
+				fail(fmt.Errorf("获取日志失败,pod:%s,%v", podName, err))
+				return
+			}
+			defer stream.Close()
+			scanner := bufio.NewScanner(stream)
+			scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+			for scanner.Scan() {
+				sendLock.Lock()
+				err = send(&pod.PodLogLine{
+					PodName:   podName,
+					Container: container,
+					Line:      scanner.Text(),
+				})
+				sendLock.Unlock()
+				if err != nil {
+					fail(err)
+					return
+				}
+			}
+			if err = scanner.Err(); err != nil && ctx.Err() == nil {
+				fail(fmt.Errorf("读取日志失败,pod:%s,%v", podName, err))
+			}
+		}(instance.Name)
+	}
+	wg.Wait()
+	return firstErr
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/known/fieldmaskpb"
+)
+
+// 以此开头的路径只修改主容器的一个环境变量
+const envPatchPrefix = "pod_envs."
+
+// 不能通过 PatchPod 修改的字段，名称、命名空间和部署类型创建后不能修改，其余字段只在查询或删除时使用
+var immutablePatchFields = map[string]bool{
+	"pod_id":          true,
+	"pod_name":        true,
+	"pod_namespace":   true,
+	"pod_deploy_type": true,
+	"version":         true,
+	"pod_qos_class":   true,
+	"delete_volumes":  true,
+}
+
+// ApplyPatch 把 patch 中 mask 列出的字段写入 info，列出的字段在 patch 中没有填写时清空
+func ApplyPatch(info *pod.PodInfo, patch *pod.PodInfo, mask *fieldmaskpb.FieldMask) error {
+	if len(mask.GetPaths()) == 0 {
+		return newValidationError("update_mask", "", errors.New("不能为空"))
+	}
+	if patch == nil {
+		patch = &pod.PodInfo{}
+	}
+	paths := map[string]bool{}
+	for _, path := range mask.GetPaths() {
+		paths[path] = true
+	}
+	target, source := info.ProtoReflect(), patch.ProtoReflect()
+	fields := target.Descriptor().Fields()
+	for _, path := range mask.GetPaths() {
+		if strings.HasPrefix(path, envPatchPrefix) {
+			key := strings.TrimPrefix(path, envPatchPrefix)
+			if key == "" {
+				return newValidationError("update_mask", path, errors.New("缺少环境变量名"))
+			}
+			if paths["pod_envs"] {
+				return newValidationError("update_mask", path, errors.New("与 pod_envs 重复"))
+			}
+			patchEnv(info, patch, key)
+			continue
+		}
+		field := fields.ByName(protoreflect.Name(path))
+		if field == nil {
+			return newValidationError("update_mask", path, errors.New("PodInfo 中没有该字段"))
+		}
+		if immutablePatchFields[path] {
+			return newValidationError("update_mask", path, errors.New("该字段不能修改"))
+		}
+		if source.Has(field) {
+			target.Set(field, source.Get(field))
+		} else {
+			target.Clear(field)
+		}
+	}
+	return nil
+}
+
+// 修改主容器的一个环境变量，沿用原来的 ID 原地更新，patch 中没有该变量时删除
+func patchEnv(info *pod.PodInfo, patch *pod.PodInfo, key string) {
+	var value *pod.PodEnv
+	for _, env := range patch.PodEnvs {
+		if env.EnvKey == key {
+			value = env
+		}
+	}
+	var envs []*pod.PodEnv
+	for _, env := range info.PodEnvs {
+		if env.EnvKey != key {
+			envs = append(envs, env)
+			continue
+		}
+		if value != nil {
+			value.Id = env.Id
+			envs = append(envs, value)
+			value = nil
+		}
+	}
+	//新增的变量
+	if value != nil {
+		value.Id = 0
+		envs = append(envs, value)
+	}
+	info.PodEnvs = envs
+}
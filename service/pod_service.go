@@ -3,15 +3,13 @@ package service
 import (
 	"context"
 	"errors"
-	"fmt"
 	"log"
-	"strconv"
 
 	"github.com/jary-287/gopass-pod/model"
 	"github.com/jary-287/gopass-pod/proto/pod"
+	"gorm.io/gorm"
 	v1 "k8s.io/api/apps/v1"
 	v12 "k8s.io/api/core/v1"
-	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
 )
@@ -22,33 +20,84 @@ type IPodService interface {
 	UpdatePod(*model.Pod) error
 	FindPodById(uint64) (*model.Pod, error)
 	FindAllPod() ([]model.Pod, error)
+	QueryPods(*pod.FindAll) ([]model.Pod, int64, string, error)
 	CreateToK8s(*pod.PodInfo) error
 	DeleteFromK8s(*pod.PodInfo) error
 	UpdateToK8s(*pod.PodInfo) error
+	ApplyServiceToK8s(*pod.PodInfo) error
+	CreateServiceToK8s(*pod.PodInfo) (bool, error)
+	DeleteServiceFromK8s(*pod.PodInfo) error
+	CheckWorkloadAbsent(*pod.PodInfo) error
+	CreateVolumeClaimsToK8s(*pod.PodInfo) ([]string, error)
+	DeleteVolumeClaimsByName(string, []string) error
+	DeleteVolumeClaimsFromK8s(*pod.PodInfo) error
+	ApplySecretToK8s(*pod.PodInfo) error
+	CreateSecretToK8s(*pod.PodInfo) (bool, error)
+	DeleteSecretFromK8s(*pod.PodInfo) error
+	GetPodStatus(*model.Pod) (*pod.PodStatus, error)
+	UpdateReplicas(uint64, int32) error
+	ScaleToK8s(*model.Pod, int32) error
+	RestartToK8s(*model.Pod) error
+	AddRevision(*pod.PodInfo, string, string) error
+	FindRevisions(uint64) ([]*pod.PodRevision, error)
+	FindRevision(uint64, int64) (*pod.PodInfo, error)
+	StreamPodLogs(context.Context, *model.Pod, *pod.PodLogRequest, func(*pod.PodLogLine) error) error
+	CheckQuota(*pod.PodInfo) error
+	SetTeamQuota(*pod.TeamQuota) error
+	GetTeamQuota(int64) (*pod.TeamQuota, error)
 }
 
 type PodService struct {
-	PodRegistry model.IPod
-	K8sClient   *kubernetes.Clientset
-	Deployment  *v1.Deployment
+	PodRegistry      model.IPod
+	RevisionRegistry model.IPodRevision
+	QuotaRegistry    model.IQuota
+	K8sClient        kubernetes.Interface
+	Deployment       *v1.Deployment
+	StatefulSet      *v1.StatefulSet
+	DaemonSet        *v1.DaemonSet
 }
 
-func NewPodService(podRegistry model.IPod, client *kubernetes.Clientset) IPodService {
+func NewPodService(podRegistry model.IPod, revisionRegistry model.IPodRevision, quotaRegistry model.IQuota, client kubernetes.Interface) IPodService {
 	return &PodService{
-		PodRegistry: podRegistry,
-		K8sClient:   client,
-		Deployment:  &v1.Deployment{},
+		PodRegistry:      podRegistry,
+		RevisionRegistry: revisionRegistry,
+		QuotaRegistry:    quotaRegistry,
+		K8sClient:        client,
+		Deployment:       &v1.Deployment{},
+		StatefulSet:      &v1.StatefulSet{},
+		DaemonSet:        &v1.DaemonSet{},
 	}
 }
 
 // AddPod implements IPodService
 func (ps *PodService) AddPod(pod *model.Pod) (uint64, error) {
-	return ps.PodRegistry.CreatePod(pod)
+	redactSecretEnvs(pod)
+	podID, err := ps.PodRegistry.CreatePod(pod)
+	return podID, NewDBError(err)
 }
 
 // CreateToK8s implements IPodService
 func (ps *PodService) CreateToK8s(pod *pod.PodInfo) error {
-	ps.SetDeployment(pod)
+	deployType, err := GetDeployType(pod.PodDeployType)
+	if err != nil {
+		return err
+	}
+	switch deployType {
+	case DeployTypeStatefulSet:
+		err = ps.createStatefulSet(pod)
+	case DeployTypeDaemonSet:
+		err = ps.createDaemonSet(pod)
+	default:
+		err = ps.createDeployment(pod)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("创建成功,", pod.PodName)
+	return nil
+}
+
+func (ps *PodService) createDeployment(pod *pod.PodInfo) error {
 	if _, err := ps.K8sClient.AppsV1().Deployments(pod.PodNamespace).Get(context.TODO(),
 		pod.PodName, metav1.GetOptions{}); err != nil {
 		ps.SetDeployment(pod)
@@ -57,18 +106,37 @@ func (ps *PodService) CreateToK8s(pod *pod.PodInfo) error {
 			return err
 		}
 	} else {
-		return errors.New(fmt.Sprintf("pod 已经存在 podName: %s", pod.PodName))
+		return NewConflictError("pod 已经存在 podName: %s", pod.PodName)
 	}
-	log.Println("创建成功,", pod.PodName)
 	return nil
 }
 
 // DeleteFromK8s implements IPodService
 func (ps *PodService) DeleteFromK8s(pod *pod.PodInfo) error {
+	deployType, err := GetDeployType(pod.PodDeployType)
+	if err != nil {
+		return err
+	}
+	switch deployType {
+	case DeployTypeStatefulSet:
+		err = ps.deleteStatefulSet(pod)
+	case DeployTypeDaemonSet:
+		err = ps.deleteDaemonSet(pod)
+	default:
+		err = ps.deleteDeployment(pod)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("pod 删除成功，", pod.PodName)
+	return nil
+}
+
+func (ps *PodService) deleteDeployment(pod *pod.PodInfo) error {
 	if _, err := ps.K8sClient.AppsV1().Deployments(pod.PodNamespace).Get(
 		context.TODO(), pod.PodName, metav1.GetOptions{},
 	); err != nil {
-		return fmt.Errorf("pod 不存在，请先创建,pod name:%s", pod.PodName)
+		return workloadGetError(err, pod.PodName)
 	} else {
 		if err = ps.K8sClient.AppsV1().Deployments(pod.PodNamespace).Delete(
 			context.TODO(),
@@ -78,38 +146,67 @@ func (ps *PodService) DeleteFromK8s(pod *pod.PodInfo) error {
 			return err
 		}
 	}
-	log.Println("pod 删除成功，", pod.PodName)
 	return nil
 }
 
 // DeletePod implements IPodService
 func (ps *PodService) DeletePod(podID uint64) error {
-	return ps.PodRegistry.DeletePod(podID)
+	return NewDBError(ps.PodRegistry.DeletePod(podID))
 }
 
 // FindAllPod implements IPodService
 func (ps *PodService) FindAllPod() ([]model.Pod, error) {
-	return ps.PodRegistry.Get()
+	pods, err := ps.PodRegistry.Get()
+	return pods, NewDBError(err)
 }
 
 // FindPodById implements IPodService
 func (ps *PodService) FindPodById(podID uint64) (*model.Pod, error) {
-	return ps.PodRegistry.GetById(podID)
+	podModel, err := ps.PodRegistry.GetById(podID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError("pod 不存在,pod id:%d", podID)
+	}
+	return podModel, NewDBError(err)
 }
 
 // UpdatePod implements IPodService
 func (ps *PodService) UpdatePod(pod *model.Pod) error {
-	return ps.PodRegistry.UpdatePod(pod)
+	redactSecretEnvs(pod)
+	return NewDBError(ps.PodRegistry.UpdatePod(pod))
 }
 
 // UpdateToK8s implements IPodService
 func (ps *PodService) UpdateToK8s(info *pod.PodInfo) error {
-	if _, err := ps.K8sClient.AppsV1().Deployments(info.PodNamespace).Get(
+	deployType, err := GetDeployType(info.PodDeployType)
+	if err != nil {
+		return err
+	}
+	switch deployType {
+	case DeployTypeStatefulSet:
+		err = ps.updateStatefulSet(info)
+	case DeployTypeDaemonSet:
+		err = ps.updateDaemonSet(info)
+	default:
+		err = ps.updateDeployment(info)
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("pod 更新成功，", info.PodName)
+	return nil
+}
+
+func (ps *PodService) updateDeployment(info *pod.PodInfo) error {
+	if current, err := ps.K8sClient.AppsV1().Deployments(info.PodNamespace).Get(
 		context.TODO(), info.PodName, metav1.GetOptions{},
 	); err != nil {
-		return errors.New(fmt.Sprintf("pod 不存在，请先创建,pod name:%s", info.PodName))
+		return workloadGetError(err, info.PodName)
 	} else {
 		ps.SetDeployment(info)
+		keepRestartAnnotation(&current.Spec.Template, &ps.Deployment.Spec.Template)
+		if err := checkAppliedVersion(&current.ObjectMeta, &ps.Deployment.ObjectMeta, info); err != nil {
+			return err
+		}
 		if _, err = ps.K8sClient.AppsV1().Deployments(info.PodNamespace).Update(
 			context.TODO(),
 			ps.Deployment,
@@ -118,7 +215,6 @@ func (ps *PodService) UpdateToK8s(info *pod.PodInfo) error {
 			return err
 		}
 	}
-	log.Println("pod 更新成功，", info.PodName)
 	return nil
 }
 
@@ -144,33 +240,52 @@ func (ps *PodService) SetDeployment(info *pod.PodInfo) {
 				"app": info.PodName,
 			},
 		},
-		Template: v12.PodTemplateSpec{
-			ObjectMeta: metav1.ObjectMeta{
-				Name:      info.PodName,
-				Namespace: info.PodNamespace,
-				Labels: map[string]string{
-					"app": info.PodName,
-				},
-			},
-			Spec: v12.PodSpec{
-				Containers: []v12.Container{
-					v12.Container{
-						Name:            info.PodName,
-						Image:           info.Image,
-						ImagePullPolicy: v12.PullPolicy(info.PodPullPolicy),
-						Ports:           ps.GetContaiinerPort(info),
-						Env:             ps.GetEnvs(info.PodEnvs),
-						Resources:       ps.GetResource(info),
-					},
-				},
+		Template: ps.GetPodTemplate(info),
+		Strategy: v1.DeploymentStrategy{},
+	}
+}
+
+// 三种工作负载共用的 pod 模板
+func (ps *PodService) GetPodTemplate(info *pod.PodInfo) v12.PodTemplateSpec {
+	container := v12.Container{
+		Name:            info.PodName,
+		Image:           info.Image,
+		ImagePullPolicy: GetPullPolicy(info.PodPullPolicy),
+		Ports:           ps.GetContaiinerPort(info),
+		Env:             ps.GetEnvs(info, info.PodName, info.PodEnvs),
+		Resources:       ps.GetResource(info),
+		Command:         info.PodCommand,
+		Args:            info.PodArgs,
+		WorkingDir:      info.PodWorkingDir,
+		SecurityContext: GetSecurityContext(info.PodSecurityContext),
+	}
+	ps.SetProbes(&container, info.PodProbes)
+	volumes, mounts := ps.GetVolumes(info)
+	container.VolumeMounts = mounts
+	sidecars, initContainers := ps.GetContainers(info)
+	return v12.PodTemplateSpec{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      info.PodName,
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app": info.PodName,
 			},
 		},
-		Strategy: v1.DeploymentStrategy{},
+		Spec: v12.PodSpec{
+			RestartPolicy:  GetRestartPolicy(info.PodRestartPolicy),
+			InitContainers: initContainers,
+			Containers:     append([]v12.Container{container}, sidecars...),
+			Volumes:        volumes,
+		},
 	}
 }
 
 func (ps *PodService) GetContaiinerPort(info *pod.PodInfo) (containerPorts []v12.ContainerPort) {
-	for _, port := range info.PodPorts {
+	return getContainerPorts(info.PodPorts)
+}
+
+func getContainerPorts(ports []*pod.PodPort) (containerPorts []v12.ContainerPort) {
+	for _, port := range ports {
 		containerPorts = append(containerPorts, v12.ContainerPort{
 			ContainerPort: port.Port,
 			Protocol:      GetProtocol(port.Protocol),
@@ -192,8 +307,21 @@ func GetProtocol(protocol string) v12.Protocol {
 	}
 }
 
-func (ps *PodService) GetEnvs(envs []*pod.PodEnv) (containerEnvs []v12.EnvVar) {
+func (ps *PodService) GetEnvs(info *pod.PodInfo, containerName string, envs []*pod.PodEnv) (containerEnvs []v12.EnvVar) {
 	for _, env := range envs {
+		if env.Secret {
+			//secret 环境变量从 pod 的 secret 中引用
+			containerEnvs = append(containerEnvs, v12.EnvVar{
+				Name: env.EnvKey,
+				ValueFrom: &v12.EnvVarSource{
+					SecretKeyRef: &v12.SecretKeySelector{
+						LocalObjectReference: v12.LocalObjectReference{Name: GetSecretName(info)},
+						Key:                  GetSecretKey(info, containerName, env.EnvKey),
+					},
+				},
+			})
+			continue
+		}
 		containerEnvs = append(containerEnvs, v12.EnvVar{
 			Name:  env.EnvKey,
 			Value: string(env.EnvValue),
@@ -202,16 +330,21 @@ func (ps *PodService) GetEnvs(envs []*pod.PodEnv) (containerEnvs []v12.EnvVar) {
 	return
 }
 
-//获取资源限制
+//获取资源限制，最小值作为 requests，最大值作为 limits
 func (ps *PodService) GetResource(info *pod.PodInfo) (source v12.ResourceRequirements) {
-	source.Limits = v12.ResourceList{
-		"cpu":    resource.MustParse(strconv.FormatFloat(float64(info.PodMaxCpuUsage), 'f', 6, 64)),
-		"memory": resource.MustParse(strconv.FormatFloat(float64(info.PodMaxMemUsage), 'f', 6, 64)),
-	}
-	source.Requests =
-		v12.ResourceList{
-			"cpu":    resource.MustParse(strconv.FormatFloat(float64(info.PodMaxCpuUsage), 'f', 6, 64)),
-			"memory": resource.MustParse(strconv.FormatFloat(float64(info.PodMaxMemUsage), 'f', 6, 64)),
-		}
+	source.Limits = v12.ResourceList{}
+	source.Requests = v12.ResourceList{}
+	if info.PodMaxCpuUsage > 0 {
+		source.Limits[v12.ResourceCPU] = CpuQuantity(info.PodMaxCpuUsage)
+	}
+	if info.PodMaxMemUsage > 0 {
+		source.Limits[v12.ResourceMemory] = MemQuantity(info.PodMaxMemUsage)
+	}
+	if info.PodMinCpuUsage > 0 {
+		source.Requests[v12.ResourceCPU] = CpuQuantity(info.PodMinCpuUsage)
+	}
+	if info.PodMinMemUsage > 0 {
+		source.Requests[v12.ResourceMemory] = MemQuantity(info.PodMinMemUsage)
+	}
 	return
 }
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+// 探针类型
+const (
+	ProbeLiveness  = "liveness"
+	ProbeReadiness = "readiness"
+	ProbeStartup   = "startup"
+)
+
+// 探针检查方式
+const (
+	ProbeHTTPGet   = "http_get"
+	ProbeTCPSocket = "tcp_socket"
+	ProbeExec      = "exec"
+	ProbeGRPC      = "grpc"
+)
+
+// SetProbes 把探针配置渲染到容器上
+func (ps *PodService) SetProbes(container *v12.Container, probes []*pod.PodProbe) {
+	for _, probe := range probes {
+		k8sProbe := GetProbe(probe)
+		switch strings.ToLower(probe.ProbeType) {
+		case ProbeLiveness:
+			container.LivenessProbe = k8sProbe
+		case ProbeReadiness:
+			container.ReadinessProbe = k8sProbe
+		case ProbeStartup:
+			container.StartupProbe = k8sProbe
+		}
+	}
+}
+
+// GetProbe 把 PodProbe 转换为 k8s 探针，未填写的数值使用 k8s 默认值
+func GetProbe(probe *pod.PodProbe) *v12.Probe {
+	k8sProbe := &v12.Probe{
+		InitialDelaySeconds: probe.InitialDelaySeconds,
+		PeriodSeconds:       probe.PeriodSeconds,
+		TimeoutSeconds:      probe.TimeoutSeconds,
+		SuccessThreshold:    probe.SuccessThreshold,
+		FailureThreshold:    probe.FailureThreshold,
+	}
+	switch strings.ToLower(probe.Handler) {
+	case ProbeHTTPGet:
+		scheme := v12.URISchemeHTTP
+		if strings.EqualFold(probe.Scheme, string(v12.URISchemeHTTPS)) {
+			scheme = v12.URISchemeHTTPS
+		}
+		k8sProbe.HTTPGet = &v12.HTTPGetAction{
+			Path:   probe.Path,
+			Port:   intstr.FromInt(int(probe.Port)),
+			Scheme: scheme,
+		}
+	case ProbeTCPSocket:
+		k8sProbe.TCPSocket = &v12.TCPSocketAction{
+			Port: intstr.FromInt(int(probe.Port)),
+		}
+	case ProbeExec:
+		k8sProbe.Exec = &v12.ExecAction{
+			Command: probe.Command,
+		}
+	case ProbeGRPC:
+		k8sProbe.GRPC = &v12.GRPCAction{
+			Port: probe.Port,
+		}
+		if probe.GrpcService != "" {
+			k8sProbe.GRPC.Service = &probe.GrpcService
+		}
+	}
+	return k8sProbe
+}
+
+// 校验探针配置，与 k8s apiserver 的校验规则保持一致
+func validateProbes(probes []*pod.PodProbe) error {
+	seen := map[string]bool{}
+	for _, probe := range probes {
+		probeType := strings.ToLower(probe.ProbeType)
+		switch probeType {
+		case ProbeLiveness, ProbeReadiness, ProbeStartup:
+		default:
+			return fmt.Errorf("不支持的探针类型: %s,可选值 liveness/readiness/startup", probe.ProbeType)
+		}
+		if seen[probeType] {
+			return fmt.Errorf("%s 探针只能配置一个", probeType)
+		}
+		seen[probeType] = true
+		switch strings.ToLower(probe.Handler) {
+		case ProbeHTTPGet:
+			if probe.Path != "" && !strings.HasPrefix(probe.Path, "/") {
+				return fmt.Errorf("%s 探针路径必须以 / 开头: %s", probeType, probe.Path)
+			}
+			if probe.Scheme != "" && !strings.EqualFold(probe.Scheme, "http") && !strings.EqualFold(probe.Scheme, "https") {
+				return fmt.Errorf("%s 探针 scheme 只支持 HTTP/HTTPS: %s", probeType, probe.Scheme)
+			}
+			fallthrough
+		case ProbeTCPSocket, ProbeGRPC:
+			if probe.Port < 1 || probe.Port > 65535 {
+				return fmt.Errorf("%s 探针端口不合法: %d", probeType, probe.Port)
+			}
+		case ProbeExec:
+			if len(probe.Command) == 0 {
+				return fmt.Errorf("%s 探针 exec 方式必须填写命令", probeType)
+			}
+		default:
+			return fmt.Errorf("不支持的探针方式: %s,可选值 http_get/tcp_socket/exec/grpc", probe.Handler)
+		}
+		if probe.InitialDelaySeconds < 0 || probe.PeriodSeconds < 0 || probe.TimeoutSeconds < 0 ||
+			probe.SuccessThreshold < 0 || probe.FailureThreshold < 0 {
+			return fmt.Errorf("%s 探针的时间和阈值不能小于 0", probeType)
+		}
+		if probeType != ProbeReadiness && probe.SuccessThreshold > 1 {
+			return fmt.Errorf("%s 探针的 success_threshold 只能为 1", probeType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"encoding/base64"
+	"encoding/json"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+)
+
+// 分页大小
+const (
+	defaultPageSize = 20
+	maxPageSize     = 500
+)
+
+// 分页 token 的内容，带上排序方式，避免换了排序之后继续使用旧 token
+type pageToken struct {
+	OrderBy string `json:"o"`
+	Desc    bool   `json:"d"`
+	model.PodCursor
+}
+
+// QueryPods 按条件分页查询 pod，返回当前页、总数和下一页的 token
+func (ps *PodService) QueryPods(req *pod.FindAll) (pods []model.Pod, total int64, nextPageToken string, err error) {
+	query := &model.PodQuery{
+		Namespace:  req.PodNamespace,
+		TeamID:     req.PodTeamId,
+		TeamIDs:    req.PodTeamIds,
+		Image:      req.Image,
+		NamePrefix: req.NamePrefix,
+		OrderBy:    req.OrderBy,
+		Desc:       req.Desc,
+	}
+	if query.OrderBy == "" {
+		query.OrderBy = "pod_id"
+	}
+	if req.PodDeployType != "" {
+		deployType, err := GetDeployType(req.PodDeployType)
+		if err != nil {
+			return nil, 0, "", newValidationError("pod_deploy_type", req.PodDeployType, err)
+		}
+		query.DeployType = []string{deployType}
+		//早期数据没有填写部署类型，都是 deployment
+		if deployType == DeployTypeDeployment {
+			query.DeployType = append(query.DeployType, "")
+		}
+	}
+	pageSize := int(req.PageSize)
+	if pageSize <= 0 {
+		pageSize = defaultPageSize
+	}
+	if pageSize > maxPageSize {
+		pageSize = maxPageSize
+	}
+	//多取一行判断是否还有下一页
+	query.Limit = pageSize + 1
+	if req.PageToken != "" {
+		token, err := decodePageToken(req.PageToken)
+		if err != nil || token.OrderBy != query.OrderBy || token.Desc != query.Desc {
+			return nil, 0, "", NewInvalidArgumentError("page_token 无效或与排序条件不一致")
+		}
+		query.After = &token.PodCursor
+	}
+	pods, total, err = ps.PodRegistry.Find(query)
+	if err != nil {
+		return nil, 0, "", NewDBError(err)
+	}
+	if len(pods) > pageSize {
+		pods = pods[:pageSize]
+		nextPageToken = encodePageToken(&pageToken{
+			OrderBy:   query.OrderBy,
+			Desc:      query.Desc,
+			PodCursor: *pods[pageSize-1].Cursor(query.OrderBy),
+		})
+	}
+	return pods, total, nextPageToken, nil
+}
+
+func encodePageToken(token *pageToken) string {
+	data, _ := json.Marshal(token)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodePageToken(value string) (*pageToken, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	token := &pageToken{}
+	if err := json.Unmarshal(data, token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
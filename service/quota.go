@@ -0,0 +1,300 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// QuotaError 表示请求超出了团队配额
+type QuotaError struct {
+	TeamID    int64
+	Resource  string
+	Used      string
+	Requested string
+	Limit     string
+}
+
+func (e *QuotaError) Error() string {
+	return fmt.Sprintf("团队 %d 的 %s 配额不足: 其他 pod 已使用 %s,本次需要 %s,上限 %s",
+		e.TeamID, e.Resource, e.Used, e.Requested, e.Limit)
+}
+
+// 一个或多个 pod 占用的资源，cpu 单位为核，内存单位为 Mi
+type quotaUsage struct {
+	cpu      float64
+	mem      float64
+	replicas int64
+	pods     int64
+	//有容器没有设置上限，配置了 cpu/内存配额时不允许
+	cpuUnlimited bool
+	memUnlimited bool
+}
+
+func (u *quotaUsage) add(other *quotaUsage) {
+	u.cpu += other.cpu
+	u.mem += other.mem
+	u.replicas += other.replicas
+	u.pods += other.pods
+}
+
+// 按 k8s 的规则计算一个副本的资源上限：普通容器之和与最大的 init 容器取较大值，再乘以副本数
+func podUsage(info *pod.PodInfo) *quotaUsage {
+	usage := &quotaUsage{pods: 1, replicas: int64(info.Replicas)}
+	//daemonset 的副本数由节点数决定，至少按一个计算
+	if deployType, _ := GetDeployType(info.PodDeployType); deployType == DeployTypeDaemonSet && usage.replicas < 1 {
+		usage.replicas = 1
+	}
+	appCpu, appMem := float64(info.PodMaxCpuUsage), float64(info.PodMaxMemUsage)
+	usage.cpuUnlimited, usage.memUnlimited = appCpu == 0, appMem == 0
+	var initCpu, initMem float64
+	for _, c := range info.PodContainers {
+		maxCpu, _ := parseCpu("max_cpu", c.MaxCpu, 0)
+		maxMem, _ := parseMem("max_mem", c.MaxMem, 0)
+		usage.cpuUnlimited = usage.cpuUnlimited || maxCpu == 0
+		usage.memUnlimited = usage.memUnlimited || maxMem == 0
+		if c.Kind == ContainerKindInit {
+			initCpu = maxFloat(initCpu, float64(maxCpu))
+			initMem = maxFloat(initMem, float64(maxMem))
+			continue
+		}
+		appCpu += float64(maxCpu)
+		appMem += float64(maxMem)
+	}
+	usage.cpu = maxFloat(appCpu, initCpu) * float64(usage.replicas)
+	usage.mem = maxFloat(appMem, initMem) * float64(usage.replicas)
+	return usage
+}
+
+// 没有配置配额存储时不检查配额，也不能设置和查询配额
+func errNoQuotaRegistry() error {
+	return newPodError(CodeInternal, "没有配置配额存储,无法管理团队配额")
+}
+
+// CheckQuota 在下发到集群之前检查团队配额，请求没有增加的资源项即使已经超额也允许，便于缩容
+func (ps *PodService) CheckQuota(info *pod.PodInfo) error {
+	if ps.QuotaRegistry == nil {
+		return nil
+	}
+	quota, err := ps.QuotaRegistry.GetQuota(info.PodTeamId)
+	if err != nil || quota == nil {
+		return NewDBError(err)
+	}
+	pods, err := ps.QuotaRegistry.GetTeamPods(info.PodTeamId)
+	if err != nil {
+		return NewDBError(err)
+	}
+	//并发创建时可能同时通过检查，ResourceQuota 同步到集群后由 k8s 兜底
+	others, current := &quotaUsage{}, &quotaUsage{}
+	for i := range pods {
+		podInfo, err := ModelToPodInfo(&pods[i])
+		if err != nil {
+			return err
+		}
+		if pods[i].PodID == info.PodId {
+			current = podUsage(podInfo)
+			continue
+		}
+		others.add(podUsage(podInfo))
+	}
+	requested := podUsage(info)
+	if quota.MaxCpu > 0 && requested.cpuUnlimited {
+		return NewInvalidArgumentError("团队 %d 配置了 cpu 配额,pod 的所有容器都必须设置 cpu 上限", info.PodTeamId)
+	}
+	if quota.MaxMem > 0 && requested.memUnlimited {
+		return NewInvalidArgumentError("团队 %d 配置了内存配额,pod 的所有容器都必须设置内存上限", info.PodTeamId)
+	}
+	exceeded := func(name string, used, requested, current, limit float64, format func(float64) string) error {
+		if limit <= 0 || used+requested <= limit || requested <= current {
+			return nil
+		}
+		return &QuotaError{TeamID: info.PodTeamId, Resource: name,
+			Used: format(used), Requested: format(requested), Limit: format(limit)}
+	}
+	if err := exceeded("cpu", others.cpu, requested.cpu, current.cpu, quota.MaxCpu, formatCpu); err != nil {
+		return err
+	}
+	if err := exceeded("内存", others.mem, requested.mem, current.mem, quota.MaxMem, formatMem); err != nil {
+		return err
+	}
+	if err := exceeded("副本数", float64(others.replicas), float64(requested.replicas), float64(current.replicas),
+		float64(quota.MaxReplicas), formatCount); err != nil {
+		return err
+	}
+	return exceeded("pod 数", float64(others.pods), float64(requested.pods), float64(current.pods),
+		float64(quota.MaxPods), formatCount)
+}
+
+// SetTeamQuota 保存团队配额并同步为各命名空间的 ResourceQuota
+func (ps *PodService) SetTeamQuota(teamQuota *pod.TeamQuota) error {
+	if ps.QuotaRegistry == nil {
+		return errNoQuotaRegistry()
+	}
+	maxCpu, err := parseCpu("max_cpu", teamQuota.MaxCpu, 0)
+	if err != nil {
+		return newValidationError("max_cpu", teamQuota.MaxCpu, err)
+	}
+	maxMem, err := parseMem("max_mem", teamQuota.MaxMem, 0)
+	if err != nil {
+		return newValidationError("max_mem", teamQuota.MaxMem, err)
+	}
+	if teamQuota.MaxReplicas < 0 || teamQuota.MaxPods < 0 {
+		return NewInvalidArgumentError("max_replicas 和 max_pods 不能小于 0")
+	}
+	for _, namespace := range teamQuota.Namespaces {
+		if errs := validation.IsDNS1123Label(namespace); len(errs) > 0 {
+			return NewInvalidArgumentError("命名空间不合法: %s", namespace)
+		}
+	}
+	previous, err := ps.QuotaRegistry.GetQuota(teamQuota.TeamId)
+	if err != nil {
+		return NewDBError(err)
+	}
+	quota := &model.TeamQuota{
+		TeamID:      teamQuota.TeamId,
+		MaxCpu:      float64(maxCpu),
+		MaxMem:      float64(maxMem),
+		MaxReplicas: teamQuota.MaxReplicas,
+		MaxPods:     teamQuota.MaxPods,
+		Namespaces:  teamQuota.Namespaces,
+	}
+	//先同步集群，失败时数据库中保持原来的配额
+	if err := ps.ApplyQuotaToK8s(quota, previous); err != nil {
+		return err
+	}
+	return NewDBError(ps.QuotaRegistry.SetQuota(quota))
+}
+
+// GetTeamQuota 返回团队配额和当前用量，没有配置配额时各项为空
+func (ps *PodService) GetTeamQuota(teamID int64) (*pod.TeamQuota, error) {
+	if ps.QuotaRegistry == nil {
+		return nil, errNoQuotaRegistry()
+	}
+	teamQuota := &pod.TeamQuota{TeamId: teamID}
+	quota, err := ps.QuotaRegistry.GetQuota(teamID)
+	if err != nil {
+		return nil, NewDBError(err)
+	}
+	if quota != nil {
+		teamQuota.MaxReplicas = quota.MaxReplicas
+		teamQuota.MaxPods = quota.MaxPods
+		teamQuota.Namespaces = quota.Namespaces
+		if quota.MaxCpu > 0 {
+			teamQuota.MaxCpu = formatCpu(quota.MaxCpu)
+		}
+		if quota.MaxMem > 0 {
+			teamQuota.MaxMem = formatMem(quota.MaxMem)
+		}
+	}
+	pods, err := ps.QuotaRegistry.GetTeamPods(teamID)
+	if err != nil {
+		return nil, NewDBError(err)
+	}
+	usage := &quotaUsage{}
+	for i := range pods {
+		podInfo, err := ModelToPodInfo(&pods[i])
+		if err != nil {
+			return nil, err
+		}
+		usage.add(podUsage(podInfo))
+	}
+	teamQuota.Usage = &pod.TeamUsage{
+		Cpu:      formatCpu(usage.cpu),
+		Mem:      formatMem(usage.mem),
+		Replicas: usage.replicas,
+		Pods:     usage.pods,
+	}
+	return teamQuota, nil
+}
+
+// GetQuotaName 返回团队在命名空间中的 ResourceQuota 名称
+func GetQuotaName(teamID int64) string {
+	return fmt.Sprintf("team-%d", teamID)
+}
+
+// ApplyQuotaToK8s 在配额的每个命名空间中创建或更新 ResourceQuota，并删除不再配置的命名空间中的
+// ResourceQuota 按命名空间生效，团队跨多个命名空间时每个命名空间都以团队总量为上限
+func (ps *PodService) ApplyQuotaToK8s(quota, previous *model.TeamQuota) error {
+	hard := v12.ResourceList{}
+	if quota.MaxCpu > 0 {
+		hard[v12.ResourceLimitsCPU] = CpuQuantity(float32(quota.MaxCpu))
+	}
+	if quota.MaxMem > 0 {
+		hard[v12.ResourceLimitsMemory] = MemQuantity(float32(quota.MaxMem))
+	}
+	//k8s 中的 pods 对应副本总数，pod 数在 k8s 中没有对应的限制
+	if quota.MaxReplicas > 0 {
+		hard[v12.ResourcePods] = *resource.NewQuantity(quota.MaxReplicas, resource.DecimalSI)
+	}
+	name := GetQuotaName(quota.TeamID)
+	namespaces := quota.Namespaces
+	if len(hard) == 0 {
+		namespaces = nil
+	}
+	for _, namespace := range namespaces {
+		resourceQuotas := ps.K8sClient.CoreV1().ResourceQuotas(namespace)
+		resourceQuota := &v12.ResourceQuota{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: namespace,
+				Labels: map[string]string{
+					"team":   fmt.Sprint(quota.TeamID),
+					"author": "ljw",
+				},
+			},
+			Spec: v12.ResourceQuotaSpec{Hard: hard},
+		}
+		current, err := resourceQuotas.Get(context.TODO(), name, metav1.GetOptions{})
+		if k8serrors.IsNotFound(err) {
+			_, err = resourceQuotas.Create(context.TODO(), resourceQuota, metav1.CreateOptions{})
+		} else if err == nil {
+			current.Labels = resourceQuota.Labels
+			current.Spec.Hard = hard
+			_, err = resourceQuotas.Update(context.TODO(), current, metav1.UpdateOptions{})
+		}
+		if err != nil {
+			return NewK8sError(fmt.Errorf("同步命名空间 %s 的 ResourceQuota 失败: %w", namespace, err))
+		}
+	}
+	if previous == nil {
+		return nil
+	}
+	for _, namespace := range previous.Namespaces {
+		if contains(namespaces, namespace) {
+			continue
+		}
+		err := ps.K8sClient.CoreV1().ResourceQuotas(namespace).Delete(context.TODO(), name, metav1.DeleteOptions{})
+		if err != nil && !k8serrors.IsNotFound(err) {
+			return NewK8sError(err)
+		}
+		log.Printf("ResourceQuota %s/%s 删除成功", namespace, name)
+	}
+	return nil
+}
+
+func formatCpu(cpu float64) string {
+	return quantityString(CpuQuantity(float32(cpu)))
+}
+
+func formatMem(mem float64) string {
+	return quantityString(MemQuantity(float32(mem)))
+}
+
+func formatCount(count float64) string {
+	return fmt.Sprint(int64(count))
+}
+
+func maxFloat(a, b float64) float64 {
+	if a > b {
+		return a
+	}
+	return b
+}
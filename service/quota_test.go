@@ -0,0 +1,23 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service/servicetest"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+// 没有配额存储时设置和查询配额返回错误而不是 panic
+func TestTeamQuotaWithoutRegistry(t *testing.T) {
+	podService := NewPodService(nil, nil, nil, fake.NewSimpleClientset())
+	if err := podService.SetTeamQuota(&pod.TeamQuota{TeamId: 1, MaxPods: 1}); err == nil || Classify(err).Code != CodeInternal {
+		t.Errorf("SetTeamQuota = %v, want INTERNAL", err)
+	}
+	if _, err := podService.GetTeamQuota(1); err == nil || Classify(err).Code != CodeInternal {
+		t.Errorf("GetTeamQuota = %v, want INTERNAL", err)
+	}
+	if err := podService.CheckQuota(servicetest.PodInfo()); err != nil {
+		t.Errorf("CheckQuota = %v, want 不检查", err)
+	}
+}
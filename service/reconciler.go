@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	appslisters "k8s.io/client-go/listers/apps/v1"
+	"k8s.io/client-go/tools/cache"
+)
+
+// 调和动作
+const (
+	ReconcileNone   = "none"
+	ReconcileCreate = "create"
+	ReconcileUpdate = "update"
+)
+
+// 调和时每次从数据库读取的 pod 数量
+const reconcilePageSize = 100
+
+// DriftReport 描述一条 pod 记录与集群中工作负载的差异
+type DriftReport struct {
+	PodID     uint64
+	PodName   string
+	Namespace string
+	Action    string
+	Fields    []string
+	Applied   bool
+	Err       error
+}
+
+func (r DriftReport) String() string {
+	state := "仅报告"
+	if r.Applied {
+		state = "已修复"
+	}
+	if r.Err != nil {
+		state = "修复失败: " + r.Err.Error()
+	}
+	return fmt.Sprintf("pod %s/%s(id:%d) %s %v %s", r.Namespace, r.PodName, r.PodID, r.Action, r.Fields, state)
+}
+
+// PodReconciler 定期对比 pod 表与集群中的工作负载，重建缺失的并修正被手工改动的字段
+type PodReconciler struct {
+	PodRegistry model.IPod
+	K8sClient   kubernetes.Interface
+	Interval    time.Duration
+	//只报告差异，不修改集群
+	DryRun bool
+	//调和使用独立的 PodService，避免和 handler 共用 Deployment 字段
+	builder           *PodService
+	factory           informers.SharedInformerFactory
+	deploymentLister  appslisters.DeploymentLister
+	statefulSetLister appslisters.StatefulSetLister
+	daemonSetLister   appslisters.DaemonSetLister
+	trigger           chan struct{}
+}
+
+func NewPodReconciler(podRegistry model.IPod, client kubernetes.Interface, interval time.Duration, dryRun bool) *PodReconciler {
+	factory := informers.NewSharedInformerFactory(client, interval)
+	return &PodReconciler{
+		PodRegistry:       podRegistry,
+		K8sClient:         client,
+		Interval:          interval,
+		DryRun:            dryRun,
+		builder:           NewPodService(podRegistry, nil, nil, client).(*PodService),
+		factory:           factory,
+		deploymentLister:  factory.Apps().V1().Deployments().Lister(),
+		statefulSetLister: factory.Apps().V1().StatefulSets().Lister(),
+		daemonSetLister:   factory.Apps().V1().DaemonSets().Lister(),
+		trigger:           make(chan struct{}, 1),
+	}
+}
+
+// Run 启动 informer 并按周期调和，直到 stopCh 关闭
+func (r *PodReconciler) Run(stopCh <-chan struct{}) {
+	//工作负载被删除时立即触发一次调和
+	onDelete := cache.ResourceEventHandlerFuncs{
+		DeleteFunc: func(obj interface{}) { r.Trigger() },
+	}
+	r.factory.Apps().V1().Deployments().Informer().AddEventHandler(onDelete)
+	r.factory.Apps().V1().StatefulSets().Informer().AddEventHandler(onDelete)
+	r.factory.Apps().V1().DaemonSets().Informer().AddEventHandler(onDelete)
+	r.factory.Start(stopCh)
+	for informerType, ok := range r.factory.WaitForCacheSync(stopCh) {
+		if !ok {
+			log.Println("informer 缓存同步失败:", informerType)
+			return
+		}
+	}
+	log.Printf("调和启动,周期 %s,仅报告 %v", r.Interval, r.DryRun)
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+	for {
+		if _, err := r.ReconcileOnce(); err != nil {
+			log.Println("调和失败:", err)
+		}
+		select {
+		case <-stopCh:
+			return
+		case <-ticker.C:
+		case <-r.trigger:
+		}
+	}
+}
+
+// Trigger 请求尽快执行一次调和
+func (r *PodReconciler) Trigger() {
+	select {
+	case r.trigger <- struct{}{}:
+	default:
+	}
+}
+
+// ReconcileOnce 分页对比所有 pod 记录并返回发现的差异
+func (r *PodReconciler) ReconcileOnce() ([]DriftReport, error) {
+	var reports []DriftReport
+	query := &model.PodQuery{Limit: reconcilePageSize}
+	for {
+		pods, _, err := r.PodRegistry.Find(query)
+		if err != nil {
+			return reports, err
+		}
+		for i := range pods {
+			report := r.reconcilePod(&pods[i])
+			if report.Action == ReconcileNone {
+				continue
+			}
+			log.Println("调和:", report)
+			reports = append(reports, report)
+		}
+		if len(pods) < reconcilePageSize {
+			return reports, nil
+		}
+		query.After = pods[len(pods)-1].Cursor(query.OrderBy)
+	}
+}
+
+func (r *PodReconciler) reconcilePod(podModel *model.Pod) DriftReport {
+	report := DriftReport{
+		PodID:     podModel.PodID,
+		PodName:   podModel.PodName,
+		Namespace: podModel.PodNameSpace,
+		Action:    ReconcileNone,
+	}
+	info, err := ModelToPodInfo(podModel)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	deployType, err := GetDeployType(info.PodDeployType)
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	var update func() error
+	switch deployType {
+	case DeployTypeStatefulSet:
+		update, err = r.diffStatefulSet(info, &report)
+	case DeployTypeDaemonSet:
+		update, err = r.diffDaemonSet(info, &report)
+	default:
+		update, err = r.diffDeployment(info, &report)
+	}
+	if k8serrors.IsNotFound(err) {
+		report.Action = ReconcileCreate
+		if !r.DryRun {
+			report.Err = r.recreate(info)
+			report.Applied = report.Err == nil
+		}
+		return report
+	}
+	//记录在集群中的更新之前读取，不能用旧配置覆盖，下一轮再对比
+	if err != nil && Classify(err).Code == CodeConflict {
+		log.Printf("调和跳过 pod %s/%s: %v", podModel.PodNameSpace, podModel.PodName, err)
+		return report
+	}
+	if err != nil {
+		report.Err = err
+		return report
+	}
+	if len(report.Fields) == 0 {
+		return report
+	}
+	report.Action = ReconcileUpdate
+	if !r.DryRun {
+		report.Err = update()
+		report.Applied = report.Err == nil
+	}
+	return report
+}
+
+// 按创建 pod 的顺序重建缺失的工作负载和它依赖的 pvc、secret、service，已存在的沿用
+// 数据库中 secret 环境变量只有掩码，secret 也被删除时无法恢复，返回错误
+func (r *PodReconciler) recreate(info *pod.PodInfo) error {
+	if _, err := r.builder.CreateVolumeClaimsToK8s(info); err != nil {
+		return err
+	}
+	if err := r.builder.ApplySecretToK8s(info); err != nil {
+		return err
+	}
+	if err := r.builder.CreateToK8s(info); err != nil {
+		return err
+	}
+	return r.builder.ApplyServiceToK8s(info)
+}
+
+func (r *PodReconciler) diffDeployment(info *pod.PodInfo, report *DriftReport) (func() error, error) {
+	live, err := r.deploymentLister.Deployments(info.PodNamespace).Get(info.PodName)
+	if err != nil {
+		return nil, err
+	}
+	r.builder.SetDeployment(info)
+	desired := r.builder.Deployment
+	//informer 缓存中的对象不能直接修改
+	updated := live.DeepCopy()
+	if err := checkAppliedVersion(&live.ObjectMeta, &updated.ObjectMeta, info); err != nil {
+		return nil, err
+	}
+	report.Fields = append(diffReplicas(desired.Spec.Replicas, &updated.Spec.Replicas),
+		diffPodTemplate(&desired.Spec.Template, &updated.Spec.Template)...)
+	return func() error {
+		_, err := r.K8sClient.AppsV1().Deployments(updated.Namespace).Update(
+			context.TODO(), updated, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+func (r *PodReconciler) diffStatefulSet(info *pod.PodInfo, report *DriftReport) (func() error, error) {
+	live, err := r.statefulSetLister.StatefulSets(info.PodNamespace).Get(info.PodName)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.builder.SetStatefulSet(info); err != nil {
+		return nil, err
+	}
+	desired := r.builder.StatefulSet
+	updated := live.DeepCopy()
+	if err := checkAppliedVersion(&live.ObjectMeta, &updated.ObjectMeta, info); err != nil {
+		return nil, err
+	}
+	report.Fields = append(diffReplicas(desired.Spec.Replicas, &updated.Spec.Replicas),
+		diffPodTemplate(&desired.Spec.Template, &updated.Spec.Template)...)
+	return func() error {
+		_, err := r.K8sClient.AppsV1().StatefulSets(updated.Namespace).Update(
+			context.TODO(), updated, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+func (r *PodReconciler) diffDaemonSet(info *pod.PodInfo, report *DriftReport) (func() error, error) {
+	live, err := r.daemonSetLister.DaemonSets(info.PodNamespace).Get(info.PodName)
+	if err != nil {
+		return nil, err
+	}
+	r.builder.SetDaemonSet(info)
+	desired := r.builder.DaemonSet
+	updated := live.DeepCopy()
+	if err := checkAppliedVersion(&live.ObjectMeta, &updated.ObjectMeta, info); err != nil {
+		return nil, err
+	}
+	//daemonset 没有副本数
+	report.Fields = diffPodTemplate(&desired.Spec.Template, &updated.Spec.Template)
+	return func() error {
+		_, err := r.K8sClient.AppsV1().DaemonSets(updated.Namespace).Update(
+			context.TODO(), updated, metav1.UpdateOptions{})
+		return err
+	}, nil
+}
+
+// 对比期望和实际的副本数，不一致时写回 live
+func diffReplicas(desired *int32, live **int32) []string {
+	if *live != nil && **live == *desired {
+		return nil
+	}
+	replicas := *desired
+	*live = &replicas
+	return []string{"replicas"}
+}
+
+// 对比期望和实际的 pod 模板中的主容器，把有差异的字段写回 live 并返回字段名
+func diffPodTemplate(desired, live *v12.PodTemplateSpec) (fields []string) {
+	want := desired.Spec.Containers[0]
+	var got *v12.Container
+	for i := range live.Spec.Containers {
+		if live.Spec.Containers[i].Name == want.Name {
+			got = &live.Spec.Containers[i]
+		}
+	}
+	if got == nil {
+		live.Spec.Containers = append(live.Spec.Containers, want)
+		return append(fields, "container")
+	}
+	if got.Image != want.Image {
+		got.Image = want.Image
+		fields = append(fields, "image")
+	}
+	if !equality.Semantic.DeepEqual(got.Env, want.Env) {
+		got.Env = want.Env
+		fields = append(fields, "env")
+	}
+	if !equality.Semantic.DeepEqual(got.Resources, want.Resources) {
+		got.Resources = want.Resources
+		fields = append(fields, "resources")
+	}
+	return fields
+}
@@ -0,0 +1,146 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service/servicetest"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 启动 informer 并等待缓存同步
+func startReconciler(t *testing.T, r *PodReconciler) {
+	t.Helper()
+	stopCh := make(chan struct{})
+	t.Cleanup(func() { close(stopCh) })
+	r.factory.Start(stopCh)
+	for informerType, ok := range r.factory.WaitForCacheSync(stopCh) {
+		if !ok {
+			t.Fatalf("informer 缓存同步失败: %v", informerType)
+		}
+	}
+}
+
+// 只写入数据库，不创建工作负载
+func addTestRow(t *testing.T, podService *PodService, info *pod.PodInfo) {
+	t.Helper()
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := podService.AddPod(podModel); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// statefulset 缺失时连同 service 一起重建，secret 沿用集群中已保存的值
+func TestReconcileRecreatesStatefulSet(t *testing.T) {
+	_, podService, client := newTestSaga(t, &v12.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "web-env", Namespace: testNamespace},
+		Data:       map[string][]byte{"TOKEN": []byte("secret-value")},
+	})
+	info := servicetest.PodInfo()
+	info.PodDeployType = DeployTypeStatefulSet
+	addTestRow(t, podService, info)
+	reconciler := NewPodReconciler(podService.PodRegistry, client, time.Minute, false)
+	startReconciler(t, reconciler)
+	reports, err := reconciler.ReconcileOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Action != ReconcileCreate || !reports[0].Applied {
+		t.Fatalf("reports = %v, want 重建一个 statefulset", reports)
+	}
+	if _, err := client.AppsV1().StatefulSets(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{}); err != nil {
+		t.Errorf("statefulset 没有重建: %v", err)
+	}
+	if !exists(t, client, "services", "web") {
+		t.Error("service 没有重建")
+	}
+	secret, err := client.CoreV1().Secrets(testNamespace).Get(context.TODO(), "web-env", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret.Data["TOKEN"]) != "secret-value" {
+		t.Errorf("secret 中的值 = %q, want 沿用 secret-value", secret.Data["TOKEN"])
+	}
+}
+
+// 被手工修改的 daemonset 镜像改回数据库中的配置
+func TestReconcileDaemonSetDrift(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	info := servicetest.PodInfo()
+	info.PodDeployType = DeployTypeDaemonSet
+	info.PodVolumes = nil
+	createTestPod(t, saga, info)
+	live, err := client.AppsV1().DaemonSets(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	live.Spec.Template.Spec.Containers[0].Image = "nginx:manual"
+	if _, err := client.AppsV1().DaemonSets(testNamespace).Update(context.TODO(), live, metav1.UpdateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	reconciler := NewPodReconciler(podService.PodRegistry, client, time.Minute, false)
+	startReconciler(t, reconciler)
+	reports, err := reconciler.ReconcileOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 1 || reports[0].Action != ReconcileUpdate || fmt.Sprint(reports[0].Fields) != "[image]" {
+		t.Fatalf("reports = %v, want 修正 image", reports)
+	}
+	live, err = client.AppsV1().DaemonSets(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image := live.Spec.Template.Spec.Containers[0].Image; image != "nginx:1.23" {
+		t.Errorf("image = %s, want nginx:1.23", image)
+	}
+}
+
+// 超过一页的记录全部参与调和
+func TestReconcilePages(t *testing.T) {
+	_, podService, client := newTestSaga(t)
+	count := reconcilePageSize + 1
+	for i := 0; i < count; i++ {
+		info := servicetest.PodInfo()
+		info.PodName = fmt.Sprintf("web-%d", i)
+		addTestRow(t, podService, info)
+	}
+	reconciler := NewPodReconciler(podService.PodRegistry, client, time.Minute, true)
+	startReconciler(t, reconciler)
+	reports, err := reconciler.ReconcileOnce()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != count {
+		t.Errorf("报告了 %d 个缺失的工作负载, want %d", len(reports), count)
+	}
+}
+
+// 读取一页记录之后落地的更新不能被调和改回旧配置
+func TestReconcileSkipsStaleRow(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	stale := createTestPod(t, saga, servicetest.PodInfo())
+	if _, err := updateTestPod(saga, stale, func(info *pod.PodInfo) { info.Image = "nginx:1.24" }); err != nil {
+		t.Fatal(err)
+	}
+	reconciler := NewPodReconciler(podService.PodRegistry, client, time.Minute, false)
+	startReconciler(t, reconciler)
+	report := reconciler.reconcilePod(stale)
+	if report.Action != ReconcileNone || report.Err != nil {
+		t.Fatalf("report = %+v, want 跳过", report)
+	}
+	live, err := client.AppsV1().Deployments(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if image := live.Spec.Template.Spec.Containers[0].Image; image != "nginx:1.24" {
+		t.Errorf("image = %s, want 保留更新后的 nginx:1.24", image)
+	}
+}
@@ -0,0 +1,116 @@
+package service
+
+import (
+	"fmt"
+	"math"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+// 内存数值的单位为 Mi
+const memUnit = 1024 * 1024
+
+// CpuQuantity 把以核为单位的 cpu 数值转换为 quantity
+func CpuQuantity(cpu float32) resource.Quantity {
+	return *resource.NewMilliQuantity(int64(math.Round(float64(cpu)*1000)), resource.DecimalSI)
+}
+
+// MemQuantity 把以 Mi 为单位的内存数值转换为 quantity
+func MemQuantity(mem float32) resource.Quantity {
+	return *resource.NewQuantity(int64(math.Round(float64(mem)*memUnit)), resource.BinarySI)
+}
+
+// NormalizeResources 解析 quantity 格式的资源配置并写回数值字段，同时校验 requests 不超过 limits
+func NormalizeResources(info *pod.PodInfo) error {
+	var err error
+	if info.PodMaxCpuUsage, err = parseCpu("pod_max_cpu", info.PodMaxCpu, info.PodMaxCpuUsage); err != nil {
+		return err
+	}
+	if info.PodMinCpuUsage, err = parseCpu("pod_min_cpu", info.PodMinCpu, info.PodMinCpuUsage); err != nil {
+		return err
+	}
+	if info.PodMaxMemUsage, err = parseMem("pod_max_mem", info.PodMaxMem, info.PodMaxMemUsage); err != nil {
+		return err
+	}
+	if info.PodMinMemUsage, err = parseMem("pod_min_mem", info.PodMinMem, info.PodMinMemUsage); err != nil {
+		return err
+	}
+	if info.PodMaxCpuUsage > 0 && info.PodMinCpuUsage > info.PodMaxCpuUsage {
+		return fmt.Errorf("cpu 最小值 %v 不能大于最大值 %v", info.PodMinCpuUsage, info.PodMaxCpuUsage)
+	}
+	if info.PodMaxMemUsage > 0 && info.PodMinMemUsage > info.PodMaxMemUsage {
+		return fmt.Errorf("内存最小值 %vMi 不能大于最大值 %vMi", info.PodMinMemUsage, info.PodMaxMemUsage)
+	}
+	FillResourceView(info)
+	return nil
+}
+
+// FillResourceView 根据数值字段填写 quantity 格式的资源配置和 QoS 等级，用于返回给调用方
+func FillResourceView(info *pod.PodInfo) {
+	info.PodMaxCpu, info.PodMinCpu, info.PodMaxMem, info.PodMinMem = "", "", "", ""
+	if info.PodMaxCpuUsage > 0 {
+		info.PodMaxCpu = quantityString(CpuQuantity(info.PodMaxCpuUsage))
+	}
+	if info.PodMinCpuUsage > 0 {
+		info.PodMinCpu = quantityString(CpuQuantity(info.PodMinCpuUsage))
+	}
+	if info.PodMaxMemUsage > 0 {
+		info.PodMaxMem = quantityString(MemQuantity(info.PodMaxMemUsage))
+	}
+	if info.PodMinMemUsage > 0 {
+		info.PodMinMem = quantityString(MemQuantity(info.PodMinMemUsage))
+	}
+	info.PodQosClass = string(GetQosClass(info))
+}
+
+// GetQosClass 按 k8s 的规则计算 QoS 等级，只设置 limits 时 requests 默认等于 limits
+func GetQosClass(info *pod.PodInfo) v12.PodQOSClass {
+	if info.PodMaxCpuUsage == 0 && info.PodMaxMemUsage == 0 &&
+		info.PodMinCpuUsage == 0 && info.PodMinMemUsage == 0 {
+		return v12.PodQOSBestEffort
+	}
+	cpuGuaranteed := info.PodMaxCpuUsage > 0 &&
+		(info.PodMinCpuUsage == 0 || info.PodMinCpuUsage == info.PodMaxCpuUsage)
+	memGuaranteed := info.PodMaxMemUsage > 0 &&
+		(info.PodMinMemUsage == 0 || info.PodMinMemUsage == info.PodMaxMemUsage)
+	if cpuGuaranteed && memGuaranteed {
+		return v12.PodQOSGuaranteed
+	}
+	return v12.PodQOSBurstable
+}
+
+func parseCpu(field, value string, fallback float32) (float32, error) {
+	if value == "" {
+		return checkNegative(field, fallback)
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s 格式错误: %s,示例 500m、2", field, value)
+	}
+	return checkNegative(field, float32(quantity.MilliValue())/1000)
+}
+
+func parseMem(field, value string, fallback float32) (float32, error) {
+	if value == "" {
+		return checkNegative(field, fallback)
+	}
+	quantity, err := resource.ParseQuantity(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s 格式错误: %s,示例 512Mi、1Gi", field, value)
+	}
+	return checkNegative(field, float32(float64(quantity.Value())/memUnit))
+}
+
+func checkNegative(field string, value float32) (float32, error) {
+	if value < 0 {
+		return 0, fmt.Errorf("%s 不能小于 0", field)
+	}
+	return value, nil
+}
+
+// Quantity 的 String 方法是指针接收者，不能直接作用在返回值上
+func quantityString(quantity resource.Quantity) string {
+	return quantity.String()
+}
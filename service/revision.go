@@ -0,0 +1,73 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"gorm.io/gorm"
+)
+
+// 版本记录的操作类型
+const (
+	RevisionCreate   = "create"
+	RevisionUpdate   = "update"
+	RevisionRollback = "rollback"
+)
+
+// AddRevision 保存一份完整的 PodInfo 快照，secret 环境变量只保存掩码
+func (ps *PodService) AddRevision(info *pod.PodInfo, action, changedBy string) error {
+	spec, err := json.Marshal(maskedCopy(info))
+	if err != nil {
+		return err
+	}
+	return NewDBError(ps.RevisionRegistry.CreateRevision(&model.PodRevision{
+		PodID:     info.PodId,
+		Spec:      string(spec),
+		Action:    action,
+		ChangedBy: changedBy,
+	}))
+}
+
+// FindRevisions 查找 pod 的所有历史版本，最新的在前
+func (ps *PodService) FindRevisions(podID uint64) ([]*pod.PodRevision, error) {
+	revisions, err := ps.RevisionRegistry.GetRevisions(podID)
+	if err != nil {
+		return nil, NewDBError(err)
+	}
+	result := make([]*pod.PodRevision, 0, len(revisions))
+	for i := range revisions {
+		spec := &pod.PodInfo{}
+		if err := json.Unmarshal([]byte(revisions[i].Spec), spec); err != nil {
+			return nil, fmt.Errorf("版本快照解析失败,pod id:%d,revision:%d", podID, revisions[i].Revision)
+		}
+		result = append(result, &pod.PodRevision{
+			PodId:     revisions[i].PodID,
+			Revision:  revisions[i].Revision,
+			Spec:      spec,
+			Action:    revisions[i].Action,
+			ChangedBy: revisions[i].ChangedBy,
+			CreatedAt: revisions[i].CreatedAt.Unix(),
+		})
+	}
+	return result, nil
+}
+
+// FindRevision 取出指定版本的 PodInfo 快照
+func (ps *PodService) FindRevision(podID uint64, revision int64) (*pod.PodInfo, error) {
+	podRevision, err := ps.RevisionRegistry.GetRevision(podID, revision)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, NewNotFoundError("版本不存在,pod id:%d,revision:%d", podID, revision)
+	}
+	if err != nil {
+		return nil, NewDBError(err)
+	}
+	spec := &pod.PodInfo{}
+	if err := json.Unmarshal([]byte(podRevision.Spec), spec); err != nil {
+		return nil, fmt.Errorf("版本快照解析失败,pod id:%d,revision:%d", podID, revision)
+	}
+	spec.PodId = podID
+	return spec, nil
+}
@@ -0,0 +1,401 @@
+package service
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+)
+
+// 操作状态
+const (
+	OperationRunning     = "running"
+	OperationFailed      = "failed"
+	OperationSucceeded   = "succeeded"
+	OperationRolledBack  = "rolled_back"
+	OperationRollbackErr = "rollback_failed"
+)
+
+// SagaStep 是一次操作中的一个步骤，Undo 在后续步骤失败时用于补偿
+type SagaStep struct {
+	Name string
+	Do   func() error
+	Undo func() error
+}
+
+// PodOperation 记录一次跨 k8s 和数据库的操作
+type PodOperation struct {
+	ID        uint64
+	Kind      string
+	PodName   string
+	Status    string
+	Done      []string
+	Err       error
+	StartedAt time.Time
+}
+
+// PodSaga 协调 k8s 与数据库的写入，任意一步失败时补偿已完成的步骤
+type PodSaga struct {
+	PodService IPodService
+	lastID     uint64
+}
+
+func NewPodSaga(podService IPodService) *PodSaga {
+	return &PodSaga{PodService: podService}
+}
+
+// Create 先创建 k8s 工作负载再写入数据库和版本记录，任一步失败时删除已创建的内容
+func (s *PodSaga) Create(info *pod.PodInfo, podModel *model.Pod, changedBy string) (uint64, error) {
+	var podID uint64
+	//补偿只删除本次新建的 pvc、secret 和 service
+	var claims []string
+	var secretCreated, serviceCreated bool
+	op := s.newOperation("create", info.PodName)
+	if err := s.PodService.CheckQuota(info); err != nil {
+		op.finish(OperationFailed, err)
+		return 0, err
+	}
+	//同名工作负载已存在时在修改集群之前失败，补偿不会删除已有工作负载的资源
+	if err := s.PodService.CheckWorkloadAbsent(info); err != nil {
+		op.finish(OperationFailed, err)
+		return 0, err
+	}
+	err := s.run(op,
+		SagaStep{
+			Name: "k8s.volumes",
+			Do: func() (err error) {
+				claims, err = s.PodService.CreateVolumeClaimsToK8s(info)
+				return
+			},
+			Undo: func() error { return s.PodService.DeleteVolumeClaimsByName(info.PodNamespace, claims) },
+		},
+		SagaStep{
+			Name: "k8s.secret",
+			Do: func() (err error) {
+				secretCreated, err = s.PodService.CreateSecretToK8s(info)
+				return
+			},
+			Undo: func() error {
+				if !secretCreated {
+					return nil
+				}
+				return s.PodService.DeleteSecretFromK8s(info)
+			},
+		},
+		SagaStep{
+			Name: "k8s.create",
+			Do:   func() error { return s.PodService.CreateToK8s(info) },
+			Undo: func() error { return s.PodService.DeleteFromK8s(info) },
+		},
+		SagaStep{
+			Name: "k8s.service",
+			Do: func() (err error) {
+				serviceCreated, err = s.PodService.CreateServiceToK8s(info)
+				return
+			},
+			Undo: func() error {
+				if !serviceCreated {
+					return nil
+				}
+				return s.PodService.DeleteServiceFromK8s(info)
+			},
+		},
+		SagaStep{
+			Name: "db.insert",
+			Do: func() (err error) {
+				podID, err = s.PodService.AddPod(podModel)
+				info.PodId, info.Version = podModel.PodID, podModel.Version
+				return
+			},
+			Undo: func() error { return s.PodService.DeletePod(podModel.PodID) },
+		},
+		SagaStep{
+			Name: "db.revision",
+			Do:   func() error { return s.PodService.AddRevision(info, RevisionCreate, changedBy) },
+		},
+	)
+	return podID, err
+}
+
+// Update 先按版本号更新数据库再更新 k8s，k8s 失败时把工作负载和数据库恢复为旧配置
+func (s *PodSaga) Update(info *pod.PodInfo, podModel *model.Pod, changedBy string) error {
+	return s.update("update", info, podModel, RevisionUpdate, changedBy)
+}
+
+// Rollback 把指定版本的快照重新下发到 k8s 和数据库，并记录为一个新版本
+func (s *PodSaga) Rollback(info *pod.PodInfo, podModel *model.Pod, revision int64, changedBy string) error {
+	return s.update("rollback", info, podModel, fmt.Sprintf("%s:%d", RevisionRollback, revision), changedBy)
+}
+
+func (s *PodSaga) update(kind string, info *pod.PodInfo, podModel *model.Pod, action, changedBy string) error {
+	op := s.newOperation(kind, info.PodName)
+	previous, err := s.PodService.FindPodById(podModel.PodID)
+	if err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	//回滚以数据库中的当前版本为准，更新必须基于当前版本，避免覆盖其他请求的修改
+	if kind == "rollback" {
+		podModel.Version = previous.Version
+	}
+	//k8s 按名称更新，修改名称、命名空间或部署类型会写到另一个工作负载上
+	if !SameWorkload(previous, info) {
+		err := NewInvalidArgumentError("pod_name、pod_namespace 和 pod_deploy_type 创建后不能修改,pod id:%d", previous.PodID)
+		op.finish(OperationFailed, err)
+		return err
+	}
+	//未填写版本的请求无法判断是否基于当前版本，要求先查询
+	if podModel.Version == 0 {
+		err := NewInvalidArgumentError("version 不能为空,请先查询 pod 获取当前版本")
+		op.finish(OperationFailed, err)
+		return err
+	}
+	if podModel.Version != previous.Version {
+		err := NewVersionConflictError(previous.PodID, podModel.Version, previous.Version)
+		op.finish(OperationFailed, err)
+		return err
+	}
+	previousInfo, err := ModelToPodInfo(previous)
+	if err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	if err := s.PodService.CheckQuota(info); err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	var claims []string
+	return s.run(op,
+		SagaStep{
+			//按版本号条件更新，并发的请求只有一个能通过，未通过的请求不会修改 k8s
+			Name: "db.update",
+			Do: func() error {
+				if err := s.PodService.UpdatePod(podModel); err != nil {
+					return err
+				}
+				//工作负载和版本快照中记录更新后的版本
+				info.Version = podModel.Version
+				return nil
+			},
+			//恢复旧配置同样按版本号条件更新，之后又被其他请求修改时返回冲突，不覆盖新的修改
+			Undo: func() error {
+				previous.Version = podModel.Version
+				return s.PodService.UpdatePod(previous)
+			},
+		},
+		SagaStep{
+			//新增的 pvc 在更新前创建，删除的存储卷保留 pvc 避免丢数据
+			Name: "k8s.volumes",
+			Do: func() (err error) {
+				claims, err = s.PodService.CreateVolumeClaimsToK8s(info)
+				return
+			},
+			Undo: func() error { return s.PodService.DeleteVolumeClaimsByName(info.PodNamespace, claims) },
+		},
+		SagaStep{
+			//数据库中只有掩码，secret 的旧值无法恢复，失败时保留新值
+			Name: "k8s.secret",
+			Do:   func() error { return s.PodService.ApplySecretToK8s(info) },
+		},
+		SagaStep{
+			Name: "k8s.update",
+			Do:   func() error { return s.PodService.UpdateToK8s(info) },
+			//以本次写入的版本恢复旧配置，集群中已经是其他请求写入的更新版本时不覆盖
+			Undo: func() error {
+				previousInfo.Version = info.Version
+				return s.PodService.UpdateToK8s(previousInfo)
+			},
+		},
+		SagaStep{
+			Name: "k8s.service",
+			Do:   func() error { return s.PodService.ApplyServiceToK8s(info) },
+			Undo: func() error { return s.PodService.ApplyServiceToK8s(previousInfo) },
+		},
+		SagaStep{
+			Name: "db.revision",
+			Do:   func() error { return s.PodService.AddRevision(info, action, changedBy) },
+		},
+	)
+}
+
+// Delete 先删除数据库记录再删除 k8s 工作负载，删除工作负载失败时重新写入记录
+func (s *PodSaga) Delete(info *pod.PodInfo) error {
+	op := s.newOperation("delete", info.PodName)
+	previous, err := s.PodService.FindPodById(info.PodId)
+	if err != nil && Classify(err).Code == CodeNotFound {
+		//数据库中没有记录，只清理集群中的残留
+		return s.run(op,
+			SagaStep{
+				Name: "k8s.service.delete",
+				Do:   func() error { return s.PodService.DeleteServiceFromK8s(info) },
+			},
+			SagaStep{
+				Name: "k8s.delete",
+				Do:   func() error { return s.PodService.DeleteFromK8s(info) },
+			},
+			s.deleteSecretStep(info),
+			s.deleteVolumesStep(info),
+		)
+	}
+	//数据库不可用时不能确定记录是否存在，不修改集群
+	if err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	previousInfo, err := ModelToPodInfo(previous)
+	if err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	return s.run(op,
+		SagaStep{
+			Name: "db.delete",
+			Do:   func() error { return s.PodService.DeletePod(previous.PodID) },
+			Undo: func() error {
+				_, err := s.PodService.AddPod(previous)
+				return err
+			},
+		},
+		SagaStep{
+			Name: "k8s.service.delete",
+			Do:   func() error { return s.PodService.DeleteServiceFromK8s(info) },
+			Undo: func() error { return s.PodService.ApplyServiceToK8s(previousInfo) },
+		},
+		SagaStep{
+			Name: "k8s.delete",
+			Do:   func() error { return s.PodService.DeleteFromK8s(info) },
+		},
+		//工作负载已经删除，无法再补偿，之后的清理失败只记录日志
+		bestEffort(s.deleteSecretStep(info)),
+		bestEffort(s.deleteVolumesStep(info)),
+	)
+}
+
+// 失败时只记录日志，不触发补偿
+func bestEffort(step SagaStep) SagaStep {
+	do := step.Do
+	step.Do = func() error {
+		if err := do(); err != nil {
+			log.Printf("步骤 %s 失败,需要手工清理: %v", step.Name, err)
+		}
+		return nil
+	}
+	return step
+}
+
+// 工作负载删除之后删除 secret
+func (s *PodSaga) deleteSecretStep(info *pod.PodInfo) SagaStep {
+	return SagaStep{
+		Name: "k8s.secret.delete",
+		Do:   func() error { return s.PodService.DeleteSecretFromK8s(info) },
+	}
+}
+
+// 调用方要求时在工作负载删除之后删除 pvc
+func (s *PodSaga) deleteVolumesStep(info *pod.PodInfo) SagaStep {
+	return SagaStep{
+		Name: "k8s.volumes.delete",
+		Do: func() error {
+			if !info.DeleteVolumes {
+				return nil
+			}
+			return s.PodService.DeleteVolumeClaimsFromK8s(info)
+		},
+	}
+}
+
+// Scale 先通过 scale 子资源扩缩容再更新数据库，写库失败时恢复原副本数
+func (s *PodSaga) Scale(podModel *model.Pod, replicas int32) error {
+	op := s.newOperation("scale", podModel.PodName)
+	previous := podModel.Replicas
+	info, err := ModelToPodInfo(podModel)
+	if err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	info.Replicas = replicas
+	if err := s.PodService.CheckQuota(info); err != nil {
+		op.finish(OperationFailed, err)
+		return err
+	}
+	return s.run(op,
+		SagaStep{
+			Name: "k8s.scale",
+			Do:   func() error { return s.PodService.ScaleToK8s(podModel, replicas) },
+			Undo: func() error { return s.PodService.ScaleToK8s(podModel, previous) },
+		},
+		SagaStep{
+			Name: "db.replicas",
+			Do:   func() error { return s.PodService.UpdateReplicas(podModel.PodID, replicas) },
+		},
+	)
+}
+
+// 依次执行各步骤，失败时逆序补偿已经完成的步骤
+func (s *PodSaga) run(op *PodOperation, steps ...SagaStep) error {
+	for i, step := range steps {
+		if err := step.Do(); err != nil {
+			err = stepError(step.Name, err)
+			log.Printf("操作 %d(%s %s) 步骤 %s 失败: %v", op.ID, op.Kind, op.PodName, step.Name, err)
+			if undoErr := s.compensate(op, steps[:i]); undoErr != nil {
+				op.finish(OperationRollbackErr, undoErr)
+				return fmt.Errorf("%w; 回滚失败: %v", err, undoErr)
+			}
+			op.finish(OperationRolledBack, err)
+			return err
+		}
+		op.Done = append(op.Done, step.Name)
+	}
+	op.finish(OperationSucceeded, nil)
+	return nil
+}
+
+// 按步骤名称的前缀给还没有错误码的错误加上 k8s 或数据库的错误码
+func stepError(name string, err error) error {
+	if Classify(err).Code != CodeInternal {
+		return err
+	}
+	switch {
+	case strings.HasPrefix(name, "k8s."):
+		return NewK8sError(err)
+	case strings.HasPrefix(name, "db."):
+		return NewDBError(err)
+	}
+	return err
+}
+
+func (s *PodSaga) compensate(op *PodOperation, done []SagaStep) error {
+	for i := len(done) - 1; i >= 0; i-- {
+		if done[i].Undo == nil {
+			continue
+		}
+		if err := done[i].Undo(); err != nil {
+			return fmt.Errorf("步骤 %s 补偿失败: %v", done[i].Name, err)
+		}
+		log.Printf("操作 %d(%s %s) 步骤 %s 已补偿", op.ID, op.Kind, op.PodName, done[i].Name)
+	}
+	return nil
+}
+
+func (s *PodSaga) newOperation(kind, podName string) *PodOperation {
+	op := &PodOperation{
+		ID:        atomic.AddUint64(&s.lastID, 1),
+		Kind:      kind,
+		PodName:   podName,
+		Status:    OperationRunning,
+		StartedAt: time.Now(),
+	}
+	log.Printf("操作 %d(%s %s) 开始", op.ID, op.Kind, op.PodName)
+	return op
+}
+
+func (op *PodOperation) finish(status string, err error) {
+	op.Status = status
+	op.Err = err
+	log.Printf("操作 %d(%s %s) 结束: %s, 已完成步骤 %v, 耗时 %s",
+		op.ID, op.Kind, op.PodName, op.Status, op.Done, time.Since(op.StartedAt))
+}
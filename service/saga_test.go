@@ -0,0 +1,590 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"github.com/jary-287/gopass-pod/service/servicetest"
+	v1 "k8s.io/api/apps/v1"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
+)
+
+const testNamespace = servicetest.Namespace
+
+// 使用假的 k8s 客户端和内存存储的 saga
+func newTestSaga(t *testing.T, objects ...runtime.Object) (*PodSaga, *PodService, *fake.Clientset) {
+	t.Helper()
+	client := fake.NewSimpleClientset(objects...)
+	//假客户端的 scale 子资源会用 Scale 替换整个对象，这里改为只修改副本数
+	client.PrependReactor("update", "deployments", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		update, ok := action.(k8stesting.UpdateAction)
+		if !ok || update.GetSubresource() != "scale" {
+			return false, nil, nil
+		}
+		scale := update.GetObject().(*autoscalingv1.Scale)
+		obj, err := client.Tracker().Get(action.GetResource(), action.GetNamespace(), scale.Name)
+		if err != nil {
+			return true, nil, err
+		}
+		deployment := obj.(*v1.Deployment).DeepCopy()
+		deployment.Spec.Replicas = &scale.Spec.Replicas
+		return true, scale, client.Tracker().Update(action.GetResource(), deployment, action.GetNamespace())
+	})
+	//假客户端没有实现 DeleteCollection，这里按标签删除
+	client.PrependReactor("delete-collection", "persistentvolumeclaims", func(action k8stesting.Action) (bool, runtime.Object, error) {
+		selector := action.(k8stesting.DeleteCollectionAction).GetListRestrictions().Labels
+		obj, err := client.Tracker().List(action.GetResource(), v12.SchemeGroupVersion.WithKind("PersistentVolumeClaim"), action.GetNamespace())
+		if err != nil {
+			return true, nil, err
+		}
+		for _, claim := range obj.(*v12.PersistentVolumeClaimList).Items {
+			if !selector.Matches(labels.Set(claim.Labels)) {
+				continue
+			}
+			if err := client.Tracker().Delete(action.GetResource(), claim.Namespace, claim.Name); err != nil {
+				return true, nil, err
+			}
+		}
+		return true, nil, nil
+	})
+	pods := model.NewMemoryPodRegistry()
+	podService := NewPodService(pods, model.NewMemoryRevisionRegistry(), model.NewMemoryQuotaRegistry(pods), client).(*PodService)
+	return NewPodSaga(podService), podService, client
+}
+
+// 按 handler 的方式创建 pod，返回数据库中的记录
+func createTestPod(t *testing.T, saga *PodSaga, info *pod.PodInfo) *model.Pod {
+	t.Helper()
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	podID, err := saga.Create(info, podModel, "tester")
+	if err != nil {
+		t.Fatalf("创建 pod 失败: %v", err)
+	}
+	created, err := saga.PodService.FindPodById(podID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return created
+}
+
+// 以数据库中的记录为基础修改后更新
+func updateTestPod(saga *PodSaga, current *model.Pod, change func(*pod.PodInfo)) (*pod.PodInfo, error) {
+	info, err := ModelToPodInfo(current)
+	if err != nil {
+		return nil, err
+	}
+	//记录中 secret 环境变量是掩码，更新时沿用集群中的值
+	change(info)
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		return nil, err
+	}
+	return info, saga.Update(info, podModel, "tester")
+}
+
+// 在指定的 k8s 请求上注入错误
+func failOn(client *fake.Clientset, verb, resource string) {
+	client.PrependReactor(verb, resource, func(action k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("injected " + verb + " " + resource)
+	})
+}
+
+func exists(t *testing.T, client *fake.Clientset, resource, name string) bool {
+	t.Helper()
+	var err error
+	ctx := context.TODO()
+	switch resource {
+	case "deployments":
+		_, err = client.AppsV1().Deployments(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	case "services":
+		_, err = client.CoreV1().Services(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	case "secrets":
+		_, err = client.CoreV1().Secrets(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	case "persistentvolumeclaims":
+		_, err = client.CoreV1().PersistentVolumeClaims(testNamespace).Get(ctx, name, metav1.GetOptions{})
+	default:
+		t.Fatalf("未知的资源 %s", resource)
+	}
+	if err != nil && !k8serrors.IsNotFound(err) {
+		t.Fatal(err)
+	}
+	return err == nil
+}
+
+func deploymentImage(t *testing.T, client *fake.Clientset, name string) string {
+	t.Helper()
+	deployment, err := client.AppsV1().Deployments(testNamespace).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return deployment.Spec.Template.Spec.Containers[0].Image
+}
+
+func TestSagaCreate(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	for resource, name := range map[string]string{
+		"deployments":            "web",
+		"services":               "web",
+		"secrets":                "web-env",
+		"persistentvolumeclaims": "web-data",
+	} {
+		if !exists(t, client, resource, name) {
+			t.Errorf("%s %s 没有创建", resource, name)
+		}
+	}
+	if created.Version != 1 {
+		t.Errorf("version = %d, want 1", created.Version)
+	}
+	for _, env := range created.PodEnvs {
+		if env.EnvKey == "TOKEN" && env.EnvValue != SecretMask {
+			t.Errorf("数据库中的 secret 环境变量 = %q, want 掩码", env.EnvValue)
+		}
+	}
+	revisions, err := podService.FindRevisions(created.PodID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(revisions) != 1 || revisions[0].Action != RevisionCreate {
+		t.Errorf("revisions = %v, want 一条 create 记录", revisions)
+	}
+}
+
+func TestSagaCreateCompensates(t *testing.T) {
+	for _, tc := range []struct {
+		verb, resource string
+	}{
+		{"create", "persistentvolumeclaims"},
+		{"create", "secrets"},
+		{"create", "deployments"},
+		{"create", "services"},
+	} {
+		t.Run(tc.verb+"_"+tc.resource, func(t *testing.T) {
+			saga, podService, client := newTestSaga(t)
+			failOn(client, tc.verb, tc.resource)
+			info := servicetest.PodInfo()
+			podModel, err := PodInfoToModel(info)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := saga.Create(info, podModel, "tester"); Classify(err).Code != CodeK8sError {
+				t.Fatalf("err = %v, want k8s 错误", err)
+			}
+			assertNothingCreated(t, podService, client)
+		})
+	}
+}
+
+// 创建 statefulset 失败时删除已经创建的 headless service
+func TestSagaCreateStatefulSetCompensates(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	failOn(client, "create", "statefulsets")
+	info := servicetest.PodInfo()
+	info.PodDeployType = DeployTypeStatefulSet
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saga.Create(info, podModel, "tester"); Classify(err).Code != CodeK8sError {
+		t.Fatalf("err = %v, want k8s 错误", err)
+	}
+	if exists(t, client, "services", GetHeadlessServiceName(info)) {
+		t.Error("headless service 没有被删除")
+	}
+	assertNothingCreated(t, podService, client)
+}
+
+// 写库失败时删除已经创建的 k8s 资源
+func TestSagaCreateCompensatesDBFailure(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	//同名的记录只在数据库中存在，写库时违反唯一约束
+	if _, err := podService.PodRegistry.CreatePod(&model.Pod{PodName: "web", PodNameSpace: testNamespace}); err != nil {
+		t.Fatal(err)
+	}
+	info := servicetest.PodInfo()
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saga.Create(info, podModel, "tester"); err == nil {
+		t.Fatal("同名记录已存在时应该失败")
+	}
+	for _, resource := range []string{"deployments", "services"} {
+		if exists(t, client, resource, "web") {
+			t.Errorf("%s web 没有被补偿删除", resource)
+		}
+	}
+	if exists(t, client, "secrets", "web-env") || exists(t, client, "persistentvolumeclaims", "web-data") {
+		t.Error("secret 或 pvc 没有被补偿删除")
+	}
+}
+
+func assertNothingCreated(t *testing.T, podService *PodService, client *fake.Clientset) {
+	t.Helper()
+	for resource, name := range map[string]string{
+		"deployments":            "web",
+		"services":               "web",
+		"secrets":                "web-env",
+		"persistentvolumeclaims": "web-data",
+	} {
+		if exists(t, client, resource, name) {
+			t.Errorf("%s %s 没有被补偿删除", resource, name)
+		}
+	}
+	pods, err := podService.FindAllPod()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(pods) != 0 {
+		t.Errorf("数据库中有 %d 条记录, want 0", len(pods))
+	}
+}
+
+// 同名工作负载已经存在时不能删除它的 pvc 和 secret
+func TestSagaCreateExistingWorkload(t *testing.T) {
+	saga, podService, client := newTestSaga(t,
+		&v12.PersistentVolumeClaim{ObjectMeta: metav1.ObjectMeta{Name: "web-data", Namespace: testNamespace}},
+		&v12.Secret{ObjectMeta: metav1.ObjectMeta{Name: "web-env", Namespace: testNamespace}},
+	)
+	//工作负载由其他途径创建，数据库中没有记录
+	if err := podService.CreateToK8s(servicetest.PodInfo()); err != nil {
+		t.Fatal(err)
+	}
+	info := servicetest.PodInfo()
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saga.Create(info, podModel, "tester"); Classify(err).Code != CodeConflict {
+		t.Fatalf("err = %v, want CONFLICT", err)
+	}
+	for resource, name := range map[string]string{
+		"deployments":            "web",
+		"secrets":                "web-env",
+		"persistentvolumeclaims": "web-data",
+	} {
+		if !exists(t, client, resource, name) {
+			t.Errorf("已有工作负载的 %s %s 被删除", resource, name)
+		}
+	}
+}
+
+// 同名 service 已存在时返回冲突，不修改也不删除它
+func TestSagaCreateExistingService(t *testing.T) {
+	legacy := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "web", Namespace: testNamespace},
+		Spec:       v12.ServiceSpec{Selector: map[string]string{"app": "legacy"}},
+	}
+	saga, podService, client := newTestSaga(t, legacy)
+	info := servicetest.PodInfo()
+	podModel, err := PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saga.Create(info, podModel, "tester"); Classify(err).Code != CodeConflict {
+		t.Fatalf("err = %v, want CONFLICT", err)
+	}
+	service, err := client.CoreV1().Services(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatalf("已有的 service 被删除: %v", err)
+	}
+	if service.Spec.Selector["app"] != "legacy" {
+		t.Errorf("selector = %v, want 保持 app=legacy", service.Spec.Selector)
+	}
+	if exists(t, client, "deployments", "web") {
+		t.Error("工作负载没有被补偿删除")
+	}
+	//写库失败时同样保留已有的 service
+	if err := client.CoreV1().Services(testNamespace).Delete(context.TODO(), "web", metav1.DeleteOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := podService.PodRegistry.CreatePod(&model.Pod{PodName: "web", PodNameSpace: testNamespace}); err != nil {
+		t.Fatal(err)
+	}
+	info.PodPorts = nil
+	if _, err := client.CoreV1().Services(testNamespace).Create(context.TODO(), legacy, metav1.CreateOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	podModel, err = PodInfoToModel(info)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := saga.Create(info, podModel, "tester"); err == nil {
+		t.Fatal("同名记录已存在时应该失败")
+	}
+	if !exists(t, client, "services", "web") {
+		t.Error("补偿删除了不是本次创建的 service")
+	}
+}
+
+func TestSagaUpdate(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	if _, err := updateTestPod(saga, created, func(info *pod.PodInfo) { info.Image = "nginx:1.24" }); err != nil {
+		t.Fatal(err)
+	}
+	if image := deploymentImage(t, client, "web"); image != "nginx:1.24" {
+		t.Errorf("image = %s, want nginx:1.24", image)
+	}
+	updated, err := podService.FindPodById(created.PodID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if updated.Version != 2 || updated.Image != "nginx:1.24" {
+		t.Errorf("数据库中 version = %d image = %s, want 2 nginx:1.24", updated.Version, updated.Image)
+	}
+	deployment, err := client.AppsV1().Deployments(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applied := deployment.Annotations[podVersionAnnotation]; applied != "2" {
+		t.Errorf("工作负载上的版本 = %q, want 2", applied)
+	}
+}
+
+func TestSagaUpdateCompensates(t *testing.T) {
+	for _, tc := range []struct {
+		verb, resource string
+	}{
+		{"create", "persistentvolumeclaims"},
+		{"update", "deployments"},
+		{"update", "services"},
+	} {
+		t.Run(tc.verb+"_"+tc.resource, func(t *testing.T) {
+			saga, podService, client := newTestSaga(t)
+			created := createTestPod(t, saga, servicetest.PodInfo())
+			failOn(client, tc.verb, tc.resource)
+			_, err := updateTestPod(saga, created, func(info *pod.PodInfo) {
+				info.Image = "nginx:1.24"
+				info.PodVolumes = append(info.PodVolumes, &pod.PodVolume{
+					Name: "cache", VolumeType: VolumePVC, StorageSize: "1Gi", MountPath: "/cache",
+				})
+			})
+			if Classify(err).Code != CodeK8sError {
+				t.Fatalf("err = %v, want k8s 错误", err)
+			}
+			if image := deploymentImage(t, client, "web"); image != "nginx:1.23" {
+				t.Errorf("集群中 image = %s, want 恢复为 nginx:1.23", image)
+			}
+			if exists(t, client, "persistentvolumeclaims", "web-cache") {
+				t.Error("本次新建的 pvc 没有被补偿删除")
+			}
+			current, err := podService.FindPodById(created.PodID)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if current.Image != "nginx:1.23" || len(current.PodVolumes) != 1 {
+				t.Errorf("数据库中 image = %s volumes = %d, want 恢复为旧配置", current.Image, len(current.PodVolumes))
+			}
+		})
+	}
+}
+
+// 基于旧版本的更新在修改 k8s 之前失败
+func TestSagaUpdateConflict(t *testing.T) {
+	saga, _, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	if _, err := updateTestPod(saga, created, func(info *pod.PodInfo) { info.Image = "nginx:1.24" }); err != nil {
+		t.Fatal(err)
+	}
+	_, err := updateTestPod(saga, created, func(info *pod.PodInfo) { info.Image = "nginx:1.25" })
+	if Classify(err).Code != CodeConflict {
+		t.Fatalf("err = %v, want CONFLICT", err)
+	}
+	if image := deploymentImage(t, client, "web"); image != "nginx:1.24" {
+		t.Errorf("image = %s, want 保留先提交的 nginx:1.24", image)
+	}
+}
+
+// 集群中已经是更新的版本时不用旧配置覆盖
+func TestUpdateToK8sRejectsOlderVersion(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	if _, err := updateTestPod(saga, created, func(info *pod.PodInfo) { info.Image = "nginx:1.24" }); err != nil {
+		t.Fatal(err)
+	}
+	stale, err := ModelToPodInfo(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := podService.UpdateToK8s(stale); Classify(err).Code != CodeConflict {
+		t.Fatalf("err = %v, want CONFLICT", err)
+	}
+	if image := deploymentImage(t, client, "web"); image != "nginx:1.24" {
+		t.Errorf("image = %s, want nginx:1.24", image)
+	}
+}
+
+func TestSagaUpdateRejectsRename(t *testing.T) {
+	for name, change := range map[string]func(*pod.PodInfo){
+		"name":        func(info *pod.PodInfo) { info.PodName = "api" },
+		"namespace":   func(info *pod.PodInfo) { info.PodNamespace = "prod" },
+		"deploy_type": func(info *pod.PodInfo) { info.PodDeployType = DeployTypeDaemonSet },
+	} {
+		t.Run(name, func(t *testing.T) {
+			saga, _, client := newTestSaga(t)
+			created := createTestPod(t, saga, servicetest.PodInfo())
+			if _, err := updateTestPod(saga, created, change); Classify(err).Code != CodeInvalidArgument {
+				t.Fatalf("err = %v, want INVALID_ARGUMENT", err)
+			}
+			if !exists(t, client, "deployments", "web") {
+				t.Error("原工作负载被修改")
+			}
+		})
+	}
+}
+
+func TestSagaUpdateRequiresVersion(t *testing.T) {
+	saga, _, _ := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	_, err := updateTestPod(saga, created, func(info *pod.PodInfo) { info.Version = 0 })
+	if Classify(err).Code != CodeInvalidArgument {
+		t.Fatalf("err = %v, want INVALID_ARGUMENT", err)
+	}
+}
+
+func TestSagaDelete(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	info, err := ModelToPodInfo(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	info.DeleteVolumes = true
+	if err := saga.Delete(info); err != nil {
+		t.Fatal(err)
+	}
+	assertNothingCreated(t, podService, client)
+}
+
+// 删除工作负载失败时恢复数据库记录和 service
+func TestSagaDeleteCompensates(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	info, err := ModelToPodInfo(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOn(client, "delete", "deployments")
+	if err := saga.Delete(info); Classify(err).Code != CodeK8sError {
+		t.Fatalf("err = %v, want k8s 错误", err)
+	}
+	if _, err := podService.FindPodById(created.PodID); err != nil {
+		t.Errorf("数据库记录没有恢复: %v", err)
+	}
+	if !exists(t, client, "services", "web") {
+		t.Error("service 没有恢复")
+	}
+}
+
+// 工作负载删除之后 secret 删除失败不恢复记录，数据库和集群保持一致
+func TestSagaDeleteCleanupIsBestEffort(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	info, err := ModelToPodInfo(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	failOn(client, "delete", "secrets")
+	if err := saga.Delete(info); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	if _, err := podService.FindPodById(created.PodID); Classify(err).Code != CodeNotFound {
+		t.Errorf("数据库记录被恢复: %v", err)
+	}
+	if exists(t, client, "deployments", "web") || exists(t, client, "services", "web") {
+		t.Error("工作负载或 service 没有删除")
+	}
+}
+
+// 查询记录失败时不能当作记录不存在去清理集群
+func TestSagaDeleteDBUnavailable(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	info, err := ModelToPodInfo(created)
+	if err != nil {
+		t.Fatal(err)
+	}
+	saga.PodService = &failingFind{IPodService: podService}
+	if err := saga.Delete(info); Classify(err).Code != CodeDBError {
+		t.Fatalf("err = %v, want 数据库错误", err)
+	}
+	for resource, name := range map[string]string{
+		"deployments": "web",
+		"services":    "web",
+		"secrets":     "web-env",
+	} {
+		if !exists(t, client, resource, name) {
+			t.Errorf("%s %s 被删除", resource, name)
+		}
+	}
+}
+
+type failingFind struct {
+	IPodService
+}
+
+func (f *failingFind) FindPodById(uint64) (*model.Pod, error) {
+	return nil, NewDBError(errors.New("injected find pod"))
+}
+
+func TestSagaScale(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	if err := saga.Scale(created, 3); err != nil {
+		t.Fatal(err)
+	}
+	if replicas := deploymentReplicas(t, client); replicas != 3 {
+		t.Errorf("集群中副本数 = %d, want 3", replicas)
+	}
+	scaled, err := podService.FindPodById(created.PodID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if scaled.Replicas != 3 {
+		t.Errorf("数据库中副本数 = %d, want 3", scaled.Replicas)
+	}
+}
+
+// 写库失败时恢复原副本数
+func TestSagaScaleCompensates(t *testing.T) {
+	saga, podService, client := newTestSaga(t)
+	created := createTestPod(t, saga, servicetest.PodInfo())
+	saga.PodService = &failingReplicas{IPodService: podService}
+	if err := saga.Scale(created, 3); Classify(err).Code != CodeDBError {
+		t.Fatalf("err = %v, want 数据库错误", err)
+	}
+	if replicas := deploymentReplicas(t, client); replicas != 1 {
+		t.Errorf("集群中副本数 = %d, want 恢复为 1", replicas)
+	}
+}
+
+type failingReplicas struct {
+	IPodService
+}
+
+func (f *failingReplicas) UpdateReplicas(uint64, int32) error {
+	return errors.New("injected update replicas")
+}
+
+func deploymentReplicas(t *testing.T, client *fake.Clientset) int32 {
+	t.Helper()
+	deployment, err := client.AppsV1().Deployments(testNamespace).Get(context.TODO(), "web", metav1.GetOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return *deployment.Spec.Replicas
+}
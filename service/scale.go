@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/jary-287/gopass-pod/model"
+	autoscalingv1 "k8s.io/api/autoscaling/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// 与 kubectl rollout restart 使用相同的注解
+const restartedAtAnnotation = "kubectl.kubernetes.io/restartedAt"
+
+// UpdateReplicas implements IPodService
+func (ps *PodService) UpdateReplicas(podID uint64, replicas int32) error {
+	return NewDBError(ps.PodRegistry.UpdateReplicas(podID, replicas))
+}
+
+// ScaleToK8s 通过 scale 子资源修改副本数
+func (ps *PodService) ScaleToK8s(podModel *model.Pod, replicas int32) error {
+	if replicas < 0 {
+		return NewInvalidArgumentError("副本数不能小于 0: %d", replicas)
+	}
+	deployType, err := GetDeployType(podModel.PodDeployType)
+	if err != nil {
+		return err
+	}
+	scale := &autoscalingv1.Scale{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      podModel.PodName,
+			Namespace: podModel.PodNameSpace,
+		},
+		Spec: autoscalingv1.ScaleSpec{Replicas: replicas},
+	}
+	switch deployType {
+	case DeployTypeStatefulSet:
+		_, err = ps.K8sClient.AppsV1().StatefulSets(podModel.PodNameSpace).UpdateScale(
+			context.TODO(), podModel.PodName, scale, metav1.UpdateOptions{})
+	case DeployTypeDaemonSet:
+		return NewInvalidArgumentError("daemonset 不支持扩缩容,pod name:%s", podModel.PodName)
+	default:
+		_, err = ps.K8sClient.AppsV1().Deployments(podModel.PodNameSpace).UpdateScale(
+			context.TODO(), podModel.PodName, scale, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	log.Printf("pod 扩缩容成功,%s 副本数 %d", podModel.PodName, replicas)
+	return nil
+}
+
+// RestartToK8s 修改 pod 模板上的注解触发滚动重启
+func (ps *PodService) RestartToK8s(podModel *model.Pod) error {
+	deployType, err := GetDeployType(podModel.PodDeployType)
+	if err != nil {
+		return err
+	}
+	patch := []byte(fmt.Sprintf(`{"spec":{"template":{"metadata":{"annotations":{"%s":"%s"}}}}}`,
+		restartedAtAnnotation, time.Now().Format(time.RFC3339)))
+	switch deployType {
+	case DeployTypeStatefulSet:
+		_, err = ps.K8sClient.AppsV1().StatefulSets(podModel.PodNameSpace).Patch(
+			context.TODO(), podModel.PodName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	case DeployTypeDaemonSet:
+		_, err = ps.K8sClient.AppsV1().DaemonSets(podModel.PodNameSpace).Patch(
+			context.TODO(), podModel.PodName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	default:
+		_, err = ps.K8sClient.AppsV1().Deployments(podModel.PodNameSpace).Patch(
+			context.TODO(), podModel.PodName, types.StrategicMergePatchType, patch, metav1.PatchOptions{})
+	}
+	if err != nil {
+		return err
+	}
+	log.Println("pod 重启成功,", podModel.PodName)
+	return nil
+}
+
+// 更新工作负载时保留上一次重启的注解，避免再次触发滚动更新
+func keepRestartAnnotation(current, desired *v12.PodTemplateSpec) {
+	restartedAt, ok := current.Annotations[restartedAtAnnotation]
+	if !ok {
+		return
+	}
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	desired.Annotations[restartedAtAnnotation] = restartedAt
+}
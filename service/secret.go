@@ -0,0 +1,180 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	"google.golang.org/protobuf/proto"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// SecretMask 替代 secret 环境变量的值写入数据库和查询结果，更新时传回掩码表示沿用集群中的值
+const SecretMask = "******"
+
+// GetSecretName 返回保存 pod secret 环境变量的 k8s secret 名称
+func GetSecretName(info *pod.PodInfo) string {
+	return info.PodName + "-env"
+}
+
+// GetSecretKey 返回环境变量在 secret 中的 key，主容器直接使用变量名，其他容器加上容器名称前缀
+func GetSecretKey(info *pod.PodInfo, containerName, envKey string) string {
+	if containerName == info.PodName {
+		return envKey
+	}
+	return containerName + "." + envKey
+}
+
+// ApplySecretToK8s 把所有容器的 secret 环境变量写入 pod 的 secret，没有 secret 环境变量时删除 secret
+func (ps *PodService) ApplySecretToK8s(info *pod.PodInfo) error {
+	data, masked := secretData(info)
+	if len(data) == 0 && len(masked) == 0 {
+		return ps.DeleteSecretFromK8s(info)
+	}
+	secrets := ps.K8sClient.CoreV1().Secrets(info.PodNamespace)
+	current, err := secrets.Get(context.TODO(), GetSecretName(info), metav1.GetOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	//值为掩码的变量沿用集群中已有的值
+	for _, key := range masked {
+		if current == nil || current.Data[key] == nil {
+			return fmt.Errorf("环境变量 %s 的值为掩码,但 secret 中没有已保存的值", key)
+		}
+		data[key] = current.Data[key]
+	}
+	secret := newSecret(info, data)
+	if k8serrors.IsNotFound(err) {
+		_, err = secrets.Create(context.TODO(), secret, metav1.CreateOptions{})
+		return err
+	}
+	current.Labels = secret.Labels
+	current.Data = secret.Data
+	current.StringData = nil
+	_, err = secrets.Update(context.TODO(), current, metav1.UpdateOptions{})
+	return err
+}
+
+// CreateSecretToK8s 创建 pod 时新建 secret，secret 已存在时返回冲突而不是覆盖，没有 secret 环境变量时不创建
+// created 表示 secret 由本次调用创建，补偿时只删除自己创建的 secret
+func (ps *PodService) CreateSecretToK8s(info *pod.PodInfo) (created bool, err error) {
+	data, masked := secretData(info)
+	if len(masked) > 0 {
+		return false, NewInvalidArgumentError("创建 pod 时环境变量 %s 的值不能为掩码", masked[0])
+	}
+	if len(data) == 0 {
+		return false, nil
+	}
+	_, err = ps.K8sClient.CoreV1().Secrets(info.PodNamespace).Create(context.TODO(), newSecret(info, data), metav1.CreateOptions{})
+	if k8serrors.IsAlreadyExists(err) {
+		return false, NewConflictError("secret 已经存在: %s", GetSecretName(info))
+	}
+	return err == nil, err
+}
+
+// 收集所有容器的 secret 环境变量，值为掩码的只返回 key
+func secretData(info *pod.PodInfo) (data map[string][]byte, masked []string) {
+	data = map[string][]byte{}
+	forEachEnv(info, func(containerName string, env *pod.PodEnv) {
+		if !env.Secret {
+			return
+		}
+		key := GetSecretKey(info, containerName, env.EnvKey)
+		if env.EnvValue == SecretMask {
+			masked = append(masked, key)
+			return
+		}
+		data[key] = []byte(env.EnvValue)
+	})
+	return
+}
+
+func newSecret(info *pod.PodInfo, data map[string][]byte) *v12.Secret {
+	return &v12.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetSecretName(info),
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app":    info.PodName,
+				"author": "ljw",
+			},
+		},
+		Type: v12.SecretTypeOpaque,
+		Data: data,
+	}
+}
+
+// DeleteSecretFromK8s 删除 pod 的 secret，不存在时忽略
+func (ps *PodService) DeleteSecretFromK8s(info *pod.PodInfo) error {
+	err := ps.K8sClient.CoreV1().Secrets(info.PodNamespace).Delete(context.TODO(), GetSecretName(info), metav1.DeleteOptions{})
+	if err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	log.Println("secret 删除成功,", GetSecretName(info))
+	return nil
+}
+
+// MaskSecretEnvs 把所有容器 secret 环境变量的值替换为掩码
+func MaskSecretEnvs(info *pod.PodInfo) {
+	forEachEnv(info, func(_ string, env *pod.PodEnv) {
+		if env.Secret {
+			env.EnvValue = SecretMask
+		}
+	})
+}
+
+// 依次遍历主容器和其他容器的环境变量
+func forEachEnv(info *pod.PodInfo, fn func(containerName string, env *pod.PodEnv)) {
+	for _, env := range info.PodEnvs {
+		fn(info.PodName, env)
+	}
+	for _, container := range info.PodContainers {
+		for _, env := range container.Envs {
+			fn(container.Name, env)
+		}
+	}
+}
+
+// 写库前去掉 secret 环境变量的明文
+func redactSecretEnvs(podModel *model.Pod) {
+	for i := range podModel.PodEnvs {
+		if podModel.PodEnvs[i].Secret {
+			podModel.PodEnvs[i].EnvValue = SecretMask
+		}
+	}
+}
+
+// 返回去掉明文的副本，用于保存版本快照
+func maskedCopy(info *pod.PodInfo) *pod.PodInfo {
+	masked := proto.Clone(info).(*pod.PodInfo)
+	MaskSecretEnvs(masked)
+	return masked
+}
+
+// 校验环境变量，secret 环境变量的名称同时作为 secret 的 key
+func validateEnvs(envs []*pod.PodEnv) error {
+	keys := map[string]bool{}
+	for _, env := range envs {
+		if errs := validation.IsEnvVarName(env.EnvKey); len(errs) > 0 {
+			return fmt.Errorf("环境变量名称不合法 %s: %s", env.EnvKey, strings.Join(errs, ","))
+		}
+		if keys[env.EnvKey] {
+			return fmt.Errorf("环境变量名称重复: %s", env.EnvKey)
+		}
+		keys[env.EnvKey] = true
+		if env.Secret {
+			if errs := validation.IsConfigMapKey(env.EnvKey); len(errs) > 0 {
+				return fmt.Errorf("secret 环境变量名称不合法 %s: %s", env.EnvKey, strings.Join(errs, ","))
+			}
+		} else if env.EnvValue == SecretMask {
+			return fmt.Errorf("环境变量 %s 不是 secret,不能使用掩码作为值", env.EnvKey)
+		}
+	}
+	return nil
+}
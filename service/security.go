@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"path"
+	"regexp"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+)
+
+var capabilityPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+
+// GetSecurityContext 把 PodSecurityContext 转换为容器的 securityContext，未填写时返回 nil
+func GetSecurityContext(sc *pod.PodSecurityContext) *v12.SecurityContext {
+	if sc == nil {
+		return nil
+	}
+	securityContext := &v12.SecurityContext{RunAsUser: sc.RunAsUser}
+	if sc.RunAsNonRoot {
+		securityContext.RunAsNonRoot = &sc.RunAsNonRoot
+	}
+	if sc.ReadOnlyRootFilesystem {
+		securityContext.ReadOnlyRootFilesystem = &sc.ReadOnlyRootFilesystem
+	}
+	if len(sc.DropCapabilities) > 0 {
+		securityContext.Capabilities = &v12.Capabilities{}
+		for _, capability := range sc.DropCapabilities {
+			securityContext.Capabilities.Drop = append(securityContext.Capabilities.Drop, v12.Capability(capability))
+		}
+	}
+	return securityContext
+}
+
+// 校验工作目录，command 和 args 中的空字符串是合法参数（如 sh -c ""），与 k8s 一样不做限制
+func validateWorkingDir(workingDir string) error {
+	if workingDir != "" && !path.IsAbs(workingDir) {
+		return fmt.Errorf("工作目录必须是绝对路径: %s", workingDir)
+	}
+	return nil
+}
+
+// 校验安全配置，与 k8s apiserver 的规则保持一致，同时拒绝必然无法启动的组合
+func validateSecurityContext(sc *pod.PodSecurityContext) error {
+	if sc == nil {
+		return nil
+	}
+	if sc.RunAsUser != nil {
+		if *sc.RunAsUser < 0 || *sc.RunAsUser > math.MaxInt32 {
+			return fmt.Errorf("run_as_user 必须在 0 到 %d 之间: %d", math.MaxInt32, *sc.RunAsUser)
+		}
+		if sc.RunAsNonRoot && *sc.RunAsUser == 0 {
+			return fmt.Errorf("run_as_non_root 为 true 时 run_as_user 不能为 0")
+		}
+	}
+	for i, capability := range sc.DropCapabilities {
+		capability = strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(capability)), "CAP_")
+		if !capabilityPattern.MatchString(capability) {
+			return fmt.Errorf("capability 名称不合法: %s", sc.DropCapabilities[i])
+		}
+		sc.DropCapabilities[i] = capability
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package servicetest 提供 service 和 handle 的测试共用的数据
+//
+// 不能依赖 service 包，service 包内部的测试也要使用
+package servicetest
+
+import "github.com/jary-287/gopass-pod/proto/pod"
+
+// Namespace 是测试 pod 所在的命名空间
+const Namespace = "default"
+
+// PodInfo 返回一个 deployment 类型的 pod，包含端口、普通和 secret 环境变量以及一个 pvc 卷
+func PodInfo() *pod.PodInfo {
+	return &pod.PodInfo{
+		PodName:        "web",
+		PodNamespace:   Namespace,
+		PodTeamId:      1,
+		PodDeployType:  "deployment",
+		Image:          "nginx:1.23",
+		Replicas:       1,
+		PodMaxCpuUsage: 1,
+		PodMinCpuUsage: 0.5,
+		PodMaxMemUsage: 256,
+		PodMinMemUsage: 128,
+		PodPorts:       []*pod.PodPort{{Port: 80, Protocol: "TCP"}},
+		PodEnvs: []*pod.PodEnv{
+			{EnvKey: "MODE", EnvValue: "prod"},
+			{EnvKey: "TOKEN", EnvValue: "secret-value", Secret: true},
+		},
+		PodVolumes: []*pod.PodVolume{
+			{Name: "data", VolumeType: "pvc", StorageSize: "1Gi", MountPath: "/data"},
+		},
+	}
+}
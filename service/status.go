@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v1 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+)
+
+// rollout 状态
+const (
+	RolloutComplete    = "complete"
+	RolloutProgressing = "progressing"
+	RolloutFailed      = "failed"
+)
+
+// GetPodStatus 查询 pod 对应工作负载的 rollout 状态以及每个实例的运行状态
+func (ps *PodService) GetPodStatus(podModel *model.Pod) (*pod.PodStatus, error) {
+	deployType, err := GetDeployType(podModel.PodDeployType)
+	if err != nil {
+		return nil, err
+	}
+	status := &pod.PodStatus{
+		PodId:         podModel.PodID,
+		PodName:       podModel.PodName,
+		PodNamespace:  podModel.PodNameSpace,
+		PodDeployType: deployType,
+	}
+	switch deployType {
+	case DeployTypeStatefulSet:
+		err = ps.setStatefulSetStatus(status)
+	case DeployTypeDaemonSet:
+		err = ps.setDaemonSetStatus(status)
+	default:
+		err = ps.setDeploymentStatus(status)
+	}
+	if err != nil {
+		return nil, err
+	}
+	pods, err := ps.K8sClient.CoreV1().Pods(podModel.PodNameSpace).List(context.TODO(), metav1.ListOptions{
+		LabelSelector: labels.SelectorFromSet(labels.Set{"app": podModel.PodName}).String(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range pods.Items {
+		status.Instances = append(status.Instances, GetInstanceStatus(&pods.Items[i]))
+	}
+	return status, nil
+}
+
+func (ps *PodService) setDeploymentStatus(status *pod.PodStatus) error {
+	deployment, err := ps.K8sClient.AppsV1().Deployments(status.PodNamespace).Get(context.TODO(),
+		status.PodName, metav1.GetOptions{})
+	if err != nil {
+		return workloadGetError(err, status.PodName)
+	}
+	if deployment.Spec.Replicas != nil {
+		status.Replicas = *deployment.Spec.Replicas
+	}
+	status.ReadyReplicas = deployment.Status.ReadyReplicas
+	status.UpdatedReplicas = deployment.Status.UpdatedReplicas
+	status.AvailableReplicas = deployment.Status.AvailableReplicas
+	status.RolloutStatus = RolloutProgressing
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type != v1.DeploymentProgressing {
+			continue
+		}
+		status.RolloutReason = condition.Reason
+		status.RolloutMessage = condition.Message
+		//与 kubectl rollout status 的判断保持一致
+		switch {
+		case condition.Reason == "ProgressDeadlineExceeded":
+			status.RolloutStatus = RolloutFailed
+		case deployment.Status.ObservedGeneration >= deployment.Generation &&
+			status.UpdatedReplicas == status.Replicas &&
+			deployment.Status.Replicas == status.UpdatedReplicas &&
+			status.AvailableReplicas == status.UpdatedReplicas:
+			status.RolloutStatus = RolloutComplete
+		}
+	}
+	return nil
+}
+
+func (ps *PodService) setStatefulSetStatus(status *pod.PodStatus) error {
+	statefulSet, err := ps.K8sClient.AppsV1().StatefulSets(status.PodNamespace).Get(context.TODO(),
+		status.PodName, metav1.GetOptions{})
+	if err != nil {
+		return workloadGetError(err, status.PodName)
+	}
+	if statefulSet.Spec.Replicas != nil {
+		status.Replicas = *statefulSet.Spec.Replicas
+	}
+	status.ReadyReplicas = statefulSet.Status.ReadyReplicas
+	status.UpdatedReplicas = statefulSet.Status.UpdatedReplicas
+	status.AvailableReplicas = statefulSet.Status.AvailableReplicas
+	status.RolloutStatus = RolloutProgressing
+	if statefulSet.Status.ObservedGeneration >= statefulSet.Generation &&
+		statefulSet.Status.UpdateRevision == statefulSet.Status.CurrentRevision &&
+		status.ReadyReplicas == status.Replicas {
+		status.RolloutStatus = RolloutComplete
+	}
+	return nil
+}
+
+func (ps *PodService) setDaemonSetStatus(status *pod.PodStatus) error {
+	daemonSet, err := ps.K8sClient.AppsV1().DaemonSets(status.PodNamespace).Get(context.TODO(),
+		status.PodName, metav1.GetOptions{})
+	if err != nil {
+		return workloadGetError(err, status.PodName)
+	}
+	status.Replicas = daemonSet.Status.DesiredNumberScheduled
+	status.ReadyReplicas = daemonSet.Status.NumberReady
+	status.UpdatedReplicas = daemonSet.Status.UpdatedNumberScheduled
+	status.AvailableReplicas = daemonSet.Status.NumberAvailable
+	status.RolloutStatus = RolloutProgressing
+	if daemonSet.Status.ObservedGeneration >= daemonSet.Generation &&
+		status.UpdatedReplicas == status.Replicas &&
+		status.AvailableReplicas == status.Replicas {
+		status.RolloutStatus = RolloutComplete
+	}
+	return nil
+}
+
+// GetInstanceStatus 汇总单个 Pod 的阶段、重启次数和最近一次退出原因
+func GetInstanceStatus(instance *v12.Pod) *pod.PodInstanceStatus {
+	status := &pod.PodInstanceStatus{
+		Name:     instance.Name,
+		Phase:    string(instance.Status.Phase),
+		NodeName: instance.Spec.NodeName,
+	}
+	for _, condition := range instance.Status.Conditions {
+		if condition.Type == v12.PodReady {
+			status.Ready = condition.Status == v12.ConditionTrue
+		}
+	}
+	for _, container := range instance.Status.ContainerStatuses {
+		status.RestartCount += container.RestartCount
+		if status.LastTerminationReason != "" {
+			continue
+		}
+		//容器当前处于终止状态时（如 Completed、Error）以当前状态为准，否则取上一次终止的原因
+		if terminated := container.State.Terminated; terminated != nil {
+			status.LastTerminationReason = terminated.Reason
+		} else if terminated := container.LastTerminationState.Terminated; terminated != nil {
+			status.LastTerminationReason = terminated.Reason
+		}
+	}
+	return status
+}
@@ -0,0 +1,45 @@
+package service
+
+import (
+	"testing"
+
+	v12 "k8s.io/api/core/v1"
+)
+
+func TestGetInstanceStatusTerminationReason(t *testing.T) {
+	for name, tc := range map[string]struct {
+		container v12.ContainerStatus
+		want      string
+	}{
+		"当前终止": {
+			container: v12.ContainerStatus{
+				State: v12.ContainerState{Terminated: &v12.ContainerStateTerminated{Reason: "Completed"}},
+			},
+			want: "Completed",
+		},
+		"当前终止优先于上一次终止": {
+			container: v12.ContainerStatus{
+				State:                v12.ContainerState{Terminated: &v12.ContainerStateTerminated{Reason: "Error"}},
+				LastTerminationState: v12.ContainerState{Terminated: &v12.ContainerStateTerminated{Reason: "OOMKilled"}},
+			},
+			want: "Error",
+		},
+		"运行中取上一次终止": {
+			container: v12.ContainerStatus{
+				State:                v12.ContainerState{Running: &v12.ContainerStateRunning{}},
+				LastTerminationState: v12.ContainerState{Terminated: &v12.ContainerStateTerminated{Reason: "OOMKilled"}},
+			},
+			want: "OOMKilled",
+		},
+		"没有终止": {
+			container: v12.ContainerStatus{State: v12.ContainerState{Running: &v12.ContainerStateRunning{}}},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			instance := &v12.Pod{Status: v12.PodStatus{ContainerStatuses: []v12.ContainerStatus{tc.container}}}
+			if got := GetInstanceStatus(instance).LastTerminationReason; got != tc.want {
+				t.Errorf("last_termination_reason = %q, want %q", got, tc.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+)
+
+// 存储的拉取策略
+const (
+	PullPolicyAlways       = "always"
+	PullPolicyIfNotPresent = "if_not_present"
+	PullPolicyNever        = "never"
+)
+
+// 存储的重启策略
+const (
+	RestartPolicyAlways    = "always"
+	RestartPolicyOnFailure = "on_failure"
+	RestartPolicyNever     = "never"
+)
+
+var pullPolicies = map[string]v12.PullPolicy{
+	PullPolicyAlways:       v12.PullAlways,
+	PullPolicyIfNotPresent: v12.PullIfNotPresent,
+	PullPolicyNever:        v12.PullNever,
+}
+
+var restartPolicies = map[string]v12.RestartPolicy{
+	RestartPolicyAlways:    v12.RestartPolicyAlways,
+	RestartPolicyOnFailure: v12.RestartPolicyOnFailure,
+	RestartPolicyNever:     v12.RestartPolicyNever,
+}
+
+// 各部署类型允许的重启策略，工作负载控制器只接受 Always
+var allowedRestartPolicies = map[string][]string{
+	DeployTypeDeployment:  {RestartPolicyAlways},
+	DeployTypeStatefulSet: {RestartPolicyAlways},
+	DeployTypeDaemonSet:   {RestartPolicyAlways},
+}
+
+// ValidationError 描述一个不合法的请求字段
+type ValidationError struct {
+	Field   string `json:"field"`
+	Value   string `json:"value"`
+	Message string `json:"message"`
+}
+
+func (e *ValidationError) Error() string {
+	return fmt.Sprintf("%s 校验失败: %s", e.Field, e.Message)
+}
+
+// JSON 用于在错误详情中返回结构化的校验结果
+func (e *ValidationError) JSON() string {
+	data, _ := json.Marshal(e)
+	return string(data)
+}
+
+func newValidationError(field, value string, err error) *ValidationError {
+	return &ValidationError{Field: field, Value: value, Message: err.Error()}
+}
+
+// ValidatePodInfo 在请求下发到集群之前校验并规范化 PodInfo
+func ValidatePodInfo(info *pod.PodInfo) error {
+	deployType, err := GetDeployType(info.PodDeployType)
+	if err != nil {
+		return newValidationError("pod_deploy_type", info.PodDeployType, err)
+	}
+	info.PodDeployType = deployType
+	if _, err := GetServiceType(info.PodServiceType); err != nil {
+		return newValidationError("pod_service_type", info.PodServiceType, err)
+	}
+	pullPolicy, err := normalizePolicy(info.PodPullPolicy, PullPolicyIfNotPresent, pullPolicies)
+	if err != nil {
+		return newValidationError("pod_pull_policy", info.PodPullPolicy, err)
+	}
+	info.PodPullPolicy = pullPolicy
+	restartPolicy, err := normalizePolicy(info.PodRestartPolicy, RestartPolicyAlways, restartPolicies)
+	if err != nil {
+		return newValidationError("pod_restart_policy", info.PodRestartPolicy, err)
+	}
+	if !contains(allowedRestartPolicies[deployType], restartPolicy) {
+		return newValidationError("pod_restart_policy", info.PodRestartPolicy,
+			fmt.Errorf("%s 只支持重启策略 %s", deployType, strings.Join(allowedRestartPolicies[deployType], "/")))
+	}
+	info.PodRestartPolicy = restartPolicy
+	if err := NormalizeResources(info); err != nil {
+		return newValidationError("resources", "", err)
+	}
+	if err := validateEnvs(info.PodEnvs); err != nil {
+		return newValidationError("pod_envs", "", err)
+	}
+	if err := validateWorkingDir(info.PodWorkingDir); err != nil {
+		return newValidationError("pod_working_dir", info.PodWorkingDir, err)
+	}
+	if err := validateSecurityContext(info.PodSecurityContext); err != nil {
+		return newValidationError("pod_security_context", "", err)
+	}
+	if err := validateContainers(info); err != nil {
+		return newValidationError("pod_containers", "", err)
+	}
+	if err := validateProbes(info.PodProbes); err != nil {
+		return newValidationError("pod_probes", "", err)
+	}
+	if err := validateVolumes(info); err != nil {
+		return newValidationError("pod_volumes", "", err)
+	}
+	return nil
+}
+
+// GetPullPolicy 把存储的拉取策略转换为 k8s 的取值
+func GetPullPolicy(policy string) v12.PullPolicy {
+	if name, err := normalizePolicy(policy, PullPolicyIfNotPresent, pullPolicies); err == nil {
+		return pullPolicies[name]
+	}
+	return v12.PullIfNotPresent
+}
+
+// GetRestartPolicy 把存储的重启策略转换为 k8s 的取值
+func GetRestartPolicy(policy string) v12.RestartPolicy {
+	if name, err := normalizePolicy(policy, RestartPolicyAlways, restartPolicies); err == nil {
+		return restartPolicies[name]
+	}
+	return v12.RestartPolicyAlways
+}
+
+// 同时接受 snake_case 和 k8s 的写法，返回 snake_case
+func normalizePolicy[T ~string](policy, defaultPolicy string, policies map[string]T) (string, error) {
+	value := strings.TrimSpace(policy)
+	if value == "" {
+		return defaultPolicy, nil
+	}
+	for name, k8sValue := range policies {
+		if strings.EqualFold(value, name) || strings.EqualFold(value, string(k8sValue)) {
+			return name, nil
+		}
+	}
+	names := make([]string, 0, len(policies))
+	for name := range policies {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "", fmt.Errorf("不支持的取值 %s,可选值 %s", policy, strings.Join(names, "/"))
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,21 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/jary-287/gopass-pod/service/servicetest"
+)
+
+func TestValidateCommand(t *testing.T) {
+	info := servicetest.PodInfo()
+	//空字符串是合法参数
+	info.PodCommand = []string{"sh", "-c", ""}
+	info.PodArgs = []string{""}
+	if err := ValidatePodInfo(info); err != nil {
+		t.Fatalf("err = %v, want nil", err)
+	}
+	info.PodWorkingDir = "app"
+	if err := ValidatePodInfo(info); Classify(err).Code != CodeInvalidArgument {
+		t.Fatalf("err = %v, want INVALID_ARGUMENT", err)
+	}
+}
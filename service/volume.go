@@ -0,0 +1,221 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// 存储卷类型
+const (
+	VolumePVC       = "pvc"
+	VolumeEmptyDir  = "empty_dir"
+	VolumeConfigMap = "config_map"
+	VolumeSecret    = "secret"
+	VolumeHostPath  = "host_path"
+)
+
+var accessModes = map[string]v12.PersistentVolumeAccessMode{
+	"":                v12.ReadWriteOnce,
+	"read_write_once": v12.ReadWriteOnce,
+	"read_only_many":  v12.ReadOnlyMany,
+	"read_write_many": v12.ReadWriteMany,
+}
+
+// GetClaimName 返回存储卷使用的 pvc 名称，未指定已有 pvc 时由本服务按 pod 名称创建
+func GetClaimName(info *pod.PodInfo, volume *pod.PodVolume) string {
+	if volume.ClaimName != "" {
+		return volume.ClaimName
+	}
+	return info.PodName + "-" + volume.Name
+}
+
+// statefulset 中需要新建的 pvc 使用 volumeClaimTemplates，每个副本一份
+func isClaimTemplate(info *pod.PodInfo, volume *pod.PodVolume) bool {
+	return volume.VolumeType == VolumePVC && volume.ClaimName == "" && info.PodDeployType == DeployTypeStatefulSet
+}
+
+// GetVolumes 生成 pod 的存储卷和主容器的挂载点
+func (ps *PodService) GetVolumes(info *pod.PodInfo) (volumes []v12.Volume, mounts []v12.VolumeMount) {
+	for _, volume := range info.PodVolumes {
+		mounts = append(mounts, v12.VolumeMount{
+			Name:      volume.Name,
+			MountPath: volume.MountPath,
+			SubPath:   volume.SubPath,
+			ReadOnly:  volume.ReadOnly,
+		})
+		if isClaimTemplate(info, volume) {
+			continue
+		}
+		k8sVolume := v12.Volume{Name: volume.Name}
+		switch volume.VolumeType {
+		case VolumePVC:
+			k8sVolume.PersistentVolumeClaim = &v12.PersistentVolumeClaimVolumeSource{
+				ClaimName: GetClaimName(info, volume),
+				ReadOnly:  volume.ReadOnly,
+			}
+		case VolumeEmptyDir:
+			k8sVolume.EmptyDir = &v12.EmptyDirVolumeSource{}
+		case VolumeConfigMap:
+			k8sVolume.ConfigMap = &v12.ConfigMapVolumeSource{
+				LocalObjectReference: v12.LocalObjectReference{Name: volume.Source},
+			}
+		case VolumeSecret:
+			k8sVolume.Secret = &v12.SecretVolumeSource{SecretName: volume.Source}
+		case VolumeHostPath:
+			k8sVolume.HostPath = &v12.HostPathVolumeSource{Path: volume.Source}
+		}
+		volumes = append(volumes, k8sVolume)
+	}
+	return
+}
+
+// GetVolumeClaim 按存储卷配置生成 pvc
+func (ps *PodService) GetVolumeClaim(info *pod.PodInfo, volume *pod.PodVolume) (v12.PersistentVolumeClaim, error) {
+	size, err := resource.ParseQuantity(volume.StorageSize)
+	if err != nil {
+		return v12.PersistentVolumeClaim{}, fmt.Errorf("存储卷 %s 大小格式错误: %s", volume.Name, volume.StorageSize)
+	}
+	claim := v12.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetClaimName(info, volume),
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app": info.PodName,
+			},
+		},
+		Spec: v12.PersistentVolumeClaimSpec{
+			AccessModes: []v12.PersistentVolumeAccessMode{accessModes[volume.AccessMode]},
+			Resources: v12.ResourceRequirements{
+				Requests: v12.ResourceList{
+					v12.ResourceStorage: size,
+				},
+			},
+		},
+	}
+	if volume.StorageClass != "" {
+		claim.Spec.StorageClassName = &volume.StorageClass
+	}
+	if isClaimTemplate(info, volume) {
+		//模板中的名称即存储卷名称，由 statefulset 控制器为每个副本生成 pvc
+		claim.ObjectMeta = metav1.ObjectMeta{Name: volume.Name}
+	}
+	return claim, nil
+}
+
+// CreateVolumeClaimsToK8s 在创建工作负载之前创建需要的 pvc，已存在的跳过，返回本次新建的 pvc 名称
+// 中途失败时删除本次已经新建的 pvc
+func (ps *PodService) CreateVolumeClaimsToK8s(info *pod.PodInfo) ([]string, error) {
+	var pending []v12.PersistentVolumeClaim
+	for _, volume := range info.PodVolumes {
+		if volume.VolumeType != VolumePVC || volume.ClaimName != "" || isClaimTemplate(info, volume) {
+			continue
+		}
+		claim, err := ps.GetVolumeClaim(info, volume)
+		if err != nil {
+			return nil, err
+		}
+		pending = append(pending, claim)
+	}
+	claims := ps.K8sClient.CoreV1().PersistentVolumeClaims(info.PodNamespace)
+	var created []string
+	for i := range pending {
+		_, err := claims.Create(context.TODO(), &pending[i], metav1.CreateOptions{})
+		if k8serrors.IsAlreadyExists(err) {
+			continue
+		}
+		if err != nil {
+			if deleteErr := ps.DeleteVolumeClaimsByName(info.PodNamespace, created); deleteErr != nil {
+				log.Println("删除已创建的 pvc 失败:", deleteErr)
+			}
+			return nil, err
+		}
+		created = append(created, pending[i].Name)
+	}
+	return created, nil
+}
+
+// DeleteVolumeClaimsByName 按名称删除 pvc，用于补偿本次新建的 pvc，不存在时忽略
+func (ps *PodService) DeleteVolumeClaimsByName(namespace string, names []string) error {
+	claims := ps.K8sClient.CoreV1().PersistentVolumeClaims(namespace)
+	for _, name := range names {
+		if err := claims.Delete(context.TODO(), name, metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+			return err
+		}
+		log.Println("pvc 删除成功,", name)
+	}
+	return nil
+}
+
+// DeleteVolumeClaimsFromK8s 删除本服务为 pod 创建的 pvc，包括 statefulset 按模板生成的
+func (ps *PodService) DeleteVolumeClaimsFromK8s(info *pod.PodInfo) error {
+	err := ps.K8sClient.CoreV1().PersistentVolumeClaims(info.PodNamespace).DeleteCollection(context.TODO(),
+		metav1.DeleteOptions{}, metav1.ListOptions{
+			LabelSelector: labels.SelectorFromSet(labels.Set{"app": info.PodName}).String(),
+		})
+	if err != nil {
+		return err
+	}
+	log.Println("pvc 删除成功,", info.PodName)
+	return nil
+}
+
+// 校验存储卷配置
+func validateVolumes(info *pod.PodInfo) error {
+	names := map[string]bool{}
+	mountPaths := map[string]bool{}
+	for _, volume := range info.PodVolumes {
+		volume.VolumeType = strings.ToLower(volume.VolumeType)
+		if errs := validation.IsDNS1123Label(volume.Name); len(errs) > 0 {
+			return fmt.Errorf("存储卷名称不合法 %s: %s", volume.Name, strings.Join(errs, ","))
+		}
+		if names[volume.Name] || (volume.Name == storageVolumeName && info.PodStorageSize != "") {
+			return fmt.Errorf("存储卷名称重复: %s", volume.Name)
+		}
+		names[volume.Name] = true
+		if !path.IsAbs(volume.MountPath) {
+			return fmt.Errorf("存储卷 %s 的挂载路径必须是绝对路径: %s", volume.Name, volume.MountPath)
+		}
+		if mountPaths[volume.MountPath] {
+			return fmt.Errorf("挂载路径重复: %s", volume.MountPath)
+		}
+		mountPaths[volume.MountPath] = true
+		if strings.HasPrefix(volume.SubPath, "/") || strings.Contains(volume.SubPath, "..") {
+			return fmt.Errorf("存储卷 %s 的 sub_path 必须是相对路径且不能包含 ..", volume.Name)
+		}
+		switch volume.VolumeType {
+		case VolumePVC:
+			if volume.ClaimName != "" {
+				break
+			}
+			if _, err := resource.ParseQuantity(volume.StorageSize); err != nil {
+				return fmt.Errorf("存储卷 %s 需要填写 claim_name 或合法的 storage_size", volume.Name)
+			}
+			if _, ok := accessModes[volume.AccessMode]; !ok {
+				return fmt.Errorf("存储卷 %s 的访问模式不支持: %s", volume.Name, volume.AccessMode)
+			}
+		case VolumeConfigMap, VolumeSecret:
+			if volume.Source == "" {
+				return fmt.Errorf("存储卷 %s 需要填写 %s 名称", volume.Name, volume.VolumeType)
+			}
+		case VolumeHostPath:
+			if !path.IsAbs(volume.Source) {
+				return fmt.Errorf("存储卷 %s 的主机路径必须是绝对路径: %s", volume.Name, volume.Source)
+			}
+		case VolumeEmptyDir:
+		default:
+			return fmt.Errorf("不支持的存储卷类型: %s,可选值 pvc/empty_dir/config_map/secret/host_path", volume.VolumeType)
+		}
+	}
+	return nil
+}
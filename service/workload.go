@@ -0,0 +1,363 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+
+	"github.com/jary-287/gopass-pod/model"
+	"github.com/jary-287/gopass-pod/proto/pod"
+	v1 "k8s.io/api/apps/v1"
+	v12 "k8s.io/api/core/v1"
+	k8serrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// 部署类型
+const (
+	DeployTypeDeployment  = "deployment"
+	DeployTypeStatefulSet = "statefulset"
+	DeployTypeDaemonSet   = "daemonset"
+)
+
+const (
+	//statefulset 数据卷名称及默认挂载目录
+	storageVolumeName  = "data"
+	defaultStoragePath = "/data"
+)
+
+// 工作负载上记录最后一次写入的 pod 版本
+const podVersionAnnotation = "gopass/pod-version"
+
+// 校验部署类型，未填写时默认为 deployment
+func GetDeployType(deployType string) (string, error) {
+	switch t := strings.ToLower(strings.TrimSpace(deployType)); t {
+	case "":
+		return DeployTypeDeployment, nil
+	case DeployTypeDeployment, DeployTypeStatefulSet, DeployTypeDaemonSet:
+		return t, nil
+	default:
+		return "", fmt.Errorf("不支持的部署类型: %s,可选值 deployment/statefulset/daemonset", deployType)
+	}
+}
+
+// 集群中已经写入了更新的版本时拒绝写入，避免并发更新时先提交的请求用旧配置覆盖后提交的请求，
+// 同时带上读取到的 resourceVersion，读取之后被修改时 k8s 返回冲突
+func checkAppliedVersion(current, desired *metav1.ObjectMeta, info *pod.PodInfo) error {
+	if applied, err := strconv.ParseInt(current.Annotations[podVersionAnnotation], 10, 64); err == nil && applied > info.Version {
+		return NewVersionConflictError(info.PodId, info.Version, applied)
+	}
+	if desired.Annotations == nil {
+		desired.Annotations = map[string]string{}
+	}
+	desired.Annotations[podVersionAnnotation] = strconv.FormatInt(info.Version, 10)
+	desired.ResourceVersion = current.ResourceVersion
+	return nil
+}
+
+// SameWorkload 判断 info 与数据库中的记录是否指向同一个工作负载，名称、命名空间和部署类型创建后不能修改
+func SameWorkload(podModel *model.Pod, info *pod.PodInfo) bool {
+	storedType, _ := GetDeployType(podModel.PodDeployType)
+	deployType, _ := GetDeployType(info.PodDeployType)
+	return info.PodName == podModel.PodName && info.PodNamespace == podModel.PodNameSpace && deployType == storedType
+}
+
+// CheckWorkloadAbsent 创建之前检查没有同名的工作负载，pvc、secret 和 service 按名称与工作负载对应，
+// 同名时创建失败的补偿会删除已有工作负载的资源
+func (ps *PodService) CheckWorkloadAbsent(info *pod.PodInfo) error {
+	apps := ps.K8sClient.AppsV1()
+	for _, get := range []func() error{
+		func() error {
+			_, err := apps.Deployments(info.PodNamespace).Get(context.TODO(), info.PodName, metav1.GetOptions{})
+			return err
+		},
+		func() error {
+			_, err := apps.StatefulSets(info.PodNamespace).Get(context.TODO(), info.PodName, metav1.GetOptions{})
+			return err
+		},
+		func() error {
+			_, err := apps.DaemonSets(info.PodNamespace).Get(context.TODO(), info.PodName, metav1.GetOptions{})
+			return err
+		},
+	} {
+		err := get()
+		if err == nil {
+			return NewConflictError("pod 已经存在 podName: %s", info.PodName)
+		}
+		if !k8serrors.IsNotFound(err) {
+			return NewK8sError(err)
+		}
+	}
+	return nil
+}
+
+// statefulset 使用的 headless service 名称
+func GetHeadlessServiceName(info *pod.PodInfo) string {
+	return info.PodName + "-headless"
+}
+
+func (ps *PodService) createStatefulSet(info *pod.PodInfo) error {
+	_, err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{})
+	if err == nil {
+		return NewConflictError("pod 已经存在 podName: %s", info.PodName)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return NewK8sError(err)
+	}
+	if err := ps.SetStatefulSet(info); err != nil {
+		return err
+	}
+	created, err := ps.applyHeadlessService(info)
+	if err != nil {
+		return err
+	}
+	if _, err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Create(
+		context.TODO(), ps.StatefulSet, metav1.CreateOptions{}); err != nil {
+		//只删除本次创建的 headless service
+		if created {
+			if deleteErr := ps.K8sClient.CoreV1().Services(info.PodNamespace).Delete(context.TODO(),
+				GetHeadlessServiceName(info), metav1.DeleteOptions{}); deleteErr != nil && !k8serrors.IsNotFound(deleteErr) {
+				log.Printf("删除 headless service %s 失败,需要手工清理: %v", GetHeadlessServiceName(info), deleteErr)
+			}
+		}
+		return err
+	}
+	return nil
+}
+
+func (ps *PodService) updateStatefulSet(info *pod.PodInfo) error {
+	current, err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{})
+	if err != nil {
+		return workloadGetError(err, info.PodName)
+	}
+	if err := ps.SetStatefulSet(info); err != nil {
+		return err
+	}
+	//volumeClaimTemplates 创建后不可修改，沿用集群中的值
+	ps.StatefulSet.Spec.VolumeClaimTemplates = current.Spec.VolumeClaimTemplates
+	keepRestartAnnotation(&current.Spec.Template, &ps.StatefulSet.Spec.Template)
+	if err := checkAppliedVersion(&current.ObjectMeta, &ps.StatefulSet.ObjectMeta, info); err != nil {
+		return err
+	}
+	if _, err := ps.applyHeadlessService(info); err != nil {
+		return err
+	}
+	if _, err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Update(
+		context.TODO(), ps.StatefulSet, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PodService) deleteStatefulSet(info *pod.PodInfo) error {
+	if _, err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{}); err != nil {
+		return workloadGetError(err, info.PodName)
+	}
+	if err := ps.K8sClient.AppsV1().StatefulSets(info.PodNamespace).Delete(
+		context.TODO(), info.PodName, metav1.DeleteOptions{}); err != nil {
+		return err
+	}
+	if err := ps.K8sClient.CoreV1().Services(info.PodNamespace).Delete(
+		context.TODO(), GetHeadlessServiceName(info), metav1.DeleteOptions{}); err != nil && !k8serrors.IsNotFound(err) {
+		return err
+	}
+	return nil
+}
+
+func (ps *PodService) createDaemonSet(info *pod.PodInfo) error {
+	_, err := ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{})
+	if err == nil {
+		return NewConflictError("pod 已经存在 podName: %s", info.PodName)
+	}
+	if !k8serrors.IsNotFound(err) {
+		return NewK8sError(err)
+	}
+	ps.SetDaemonSet(info)
+	if _, err := ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Create(
+		context.TODO(), ps.DaemonSet, metav1.CreateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PodService) updateDaemonSet(info *pod.PodInfo) error {
+	current, err := ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{})
+	if err != nil {
+		return workloadGetError(err, info.PodName)
+	}
+	ps.SetDaemonSet(info)
+	keepRestartAnnotation(&current.Spec.Template, &ps.DaemonSet.Spec.Template)
+	if err := checkAppliedVersion(&current.ObjectMeta, &ps.DaemonSet.ObjectMeta, info); err != nil {
+		return err
+	}
+	if _, err := ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Update(
+		context.TODO(), ps.DaemonSet, metav1.UpdateOptions{}); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (ps *PodService) deleteDaemonSet(info *pod.PodInfo) error {
+	if _, err := ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Get(context.TODO(),
+		info.PodName, metav1.GetOptions{}); err != nil {
+		return workloadGetError(err, info.PodName)
+	}
+	return ps.K8sClient.AppsV1().DaemonSets(info.PodNamespace).Delete(
+		context.TODO(), info.PodName, metav1.DeleteOptions{})
+}
+
+func (ps *PodService) SetStatefulSet(info *pod.PodInfo) error {
+	template := ps.GetPodTemplate(info)
+	claims, err := ps.GetVolumeClaimTemplates(info)
+	if err != nil {
+		return err
+	}
+	if info.PodStorageSize != "" {
+		mountPath := info.PodStoragePath
+		if mountPath == "" {
+			mountPath = defaultStoragePath
+		}
+		template.Spec.Containers[0].VolumeMounts = append(template.Spec.Containers[0].VolumeMounts, v12.VolumeMount{
+			Name:      storageVolumeName,
+			MountPath: mountPath,
+		})
+	}
+	ps.StatefulSet = &v1.StatefulSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "StatefulSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      info.PodName,
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app":    info.PodName,
+				"author": "ljw",
+			},
+		},
+		Spec: v1.StatefulSetSpec{
+			Replicas:    &info.Replicas,
+			ServiceName: GetHeadlessServiceName(info),
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": info.PodName,
+				},
+			},
+			Template:             template,
+			VolumeClaimTemplates: claims,
+		},
+	}
+	return nil
+}
+
+func (ps *PodService) SetDaemonSet(info *pod.PodInfo) {
+	ps.DaemonSet = &v1.DaemonSet{
+		TypeMeta: metav1.TypeMeta{
+			Kind:       "DaemonSet",
+			APIVersion: "apps/v1",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      info.PodName,
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app":    info.PodName,
+				"author": "ljw",
+			},
+		},
+		Spec: v1.DaemonSetSpec{
+			Selector: &metav1.LabelSelector{
+				MatchLabels: map[string]string{
+					"app": info.PodName,
+				},
+			},
+			Template: ps.GetPodTemplate(info),
+		},
+	}
+}
+
+// 根据存储大小和 pvc 类型的存储卷生成 volumeClaimTemplates
+func (ps *PodService) GetVolumeClaimTemplates(info *pod.PodInfo) ([]v12.PersistentVolumeClaim, error) {
+	var claims []v12.PersistentVolumeClaim
+	if info.PodStorageSize != "" {
+		size, err := resource.ParseQuantity(info.PodStorageSize)
+		if err != nil {
+			return nil, fmt.Errorf("存储大小格式错误: %s", info.PodStorageSize)
+		}
+		claim := v12.PersistentVolumeClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name: storageVolumeName,
+			},
+			Spec: v12.PersistentVolumeClaimSpec{
+				AccessModes: []v12.PersistentVolumeAccessMode{v12.ReadWriteOnce},
+				Resources: v12.ResourceRequirements{
+					Requests: v12.ResourceList{
+						v12.ResourceStorage: size,
+					},
+				},
+			},
+		}
+		if info.PodStorageClass != "" {
+			claim.Spec.StorageClassName = &info.PodStorageClass
+		}
+		claims = append(claims, claim)
+	}
+	for _, volume := range info.PodVolumes {
+		if !isClaimTemplate(info, volume) {
+			continue
+		}
+		claim, err := ps.GetVolumeClaim(info, volume)
+		if err != nil {
+			return nil, err
+		}
+		claims = append(claims, claim)
+	}
+	return claims, nil
+}
+
+// 创建或更新 statefulset 的 headless service，created 表示本次是否新建
+func (ps *PodService) applyHeadlessService(info *pod.PodInfo) (created bool, err error) {
+	service := &v12.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      GetHeadlessServiceName(info),
+			Namespace: info.PodNamespace,
+			Labels: map[string]string{
+				"app": info.PodName,
+			},
+		},
+		Spec: v12.ServiceSpec{
+			ClusterIP: v12.ClusterIPNone,
+			Selector: map[string]string{
+				"app": info.PodName,
+			},
+		},
+	}
+	for _, port := range info.PodPorts {
+		service.Spec.Ports = append(service.Spec.Ports, v12.ServicePort{
+			Name:     getServicePortName(port),
+			Port:     port.Port,
+			Protocol: GetProtocol(port.Protocol),
+		})
+	}
+	services := ps.K8sClient.CoreV1().Services(info.PodNamespace)
+	current, err := services.Get(context.TODO(), service.Name, metav1.GetOptions{})
+	if k8serrors.IsNotFound(err) {
+		_, err = services.Create(context.TODO(), service, metav1.CreateOptions{})
+		return err == nil, err
+	}
+	if err != nil {
+		return false, err
+	}
+	current.Labels = service.Labels
+	current.Spec.Selector = service.Spec.Selector
+	current.Spec.Ports = service.Spec.Ports
+	_, err = services.Update(context.TODO(), current, metav1.UpdateOptions{})
+	return false, err
+}